@@ -1,10 +1,16 @@
 package main
 
 import (
+	"flag"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
 
 	"thujareader/internal/config"
 	"thujareader/internal/reader"
@@ -13,10 +19,34 @@ import (
 )
 
 func main() {
+	dataDir := flag.String("data-dir", "", "directory holding config.json and state.json (overrides the per-user defaults)")
+	acceptDrops := flag.Bool("accept-drops", false, "start without a book and open book files dropped onto the terminal window")
+	dumpOutput := flag.String("dump-text", "", "write the book's text to this file (\"-\" for standard output) and exit without starting the reader")
+	flag.Parse()
+
+	// All books, whether opened here or from the UI, are loaded by the
+	// same reader, so formats registered on it work everywhere.
+	unified := reader.NewDefaultUnifiedReader()
+
+	// With --dump-text the program is a format converter for scripts:
+	// it needs neither the config nor the state.
+	if *dumpOutput != "" {
+		if err := dumpText(unified, flag.Arg(0), *dumpOutput); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Resolve configuration and state file paths.
-	paths, err := config.DefaultPaths()
-	if err != nil {
-		log.Fatal(err)
+	var paths config.Paths
+	if *dataDir != "" {
+		paths = config.PathsInDir(*dataDir)
+	} else {
+		var err error
+		paths, err = config.DefaultPaths()
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	// Load configuration; on error, fall back to defaults but continue.
@@ -32,14 +62,57 @@ func main() {
 		log.Printf("warning: failed to load state: %v", err)
 	}
 
+	// The book to open comes from the command line or, when a launcher
+	// hands over a file dropped onto the program, from DROPPED_FILE.
+	bookArg := flag.Arg(0)
+	if bookArg == "" {
+		bookArg = os.Getenv("DROPPED_FILE")
+	}
+
+	// Read the book from standard input for "thujareader -" or when input
+	// is piped; the TUI then reads keys from the terminal instead.
+	fromStdin := bookArg == "-" || (bookArg == "" && !term.IsTerminal(int(os.Stdin.Fd())))
+
 	var initialBook *reader.LoadedBook
-	if len(os.Args) > 1 {
-		unified := reader.NewDefaultUnifiedReader()
-		book, err := unified.Open(os.Args[1])
+	var tabs []reader.LoadedBook
+	activeTab := 0
+	if fromStdin {
+		book, err := unified.OpenStream(os.Stdin, "stdin")
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, w := range reader.ValidateBook(book) {
+			log.Printf("warning: stdin: %s", w)
+		}
+		initialBook = &book
+	} else if bookArg != "" {
+		book, err := unified.Open(bookArg)
 		if err != nil {
 			log.Fatal(err)
 		}
+		for _, w := range reader.ValidateBook(book) {
+			log.Printf("warning: %s: %s", bookArg, w)
+		}
 		initialBook = &book
+	} else if appState.LastOpenedBookPath != "" && !*acceptDrops {
+		// Auto-resume: reopen the books that were open in tabs at the
+		// last exit. Unlike an explicit argument, a book that has since
+		// been moved or deleted is not fatal.
+		paths := appState.OpenBookPaths
+		if len(paths) == 0 {
+			paths = []string{appState.LastOpenedBookPath}
+		}
+		for _, path := range paths {
+			book, err := unified.Open(path)
+			if err != nil {
+				log.Printf("warning: failed to reopen %s: %v", path, err)
+				continue
+			}
+			if path == appState.LastOpenedBookPath {
+				activeTab = len(tabs)
+			}
+			tabs = append(tabs, book)
+		}
 	}
 
 	// Adapt stored bookmarks (keyed by string) to the UI's map keyed by
@@ -49,29 +122,103 @@ func main() {
 		loadedBookmarks[reader.BookID(k)] = v
 	}
 
-	model := ui.NewModelWithInitialBookAndBookmarks(initialBook, loadedBookmarks)
-	// Apply configuration options that the UI currently understands.
-	if cfg.RecentListSize > 0 {
-		model.SetRecentLimit(cfg.RecentListSize)
+	loadedPositions := make(map[reader.BookID]reader.Position)
+	for k, v := range appState.Positions {
+		loadedPositions[reader.BookID(k)] = v
+	}
+
+	loadedNotes := make(map[reader.BookID][]reader.Note)
+	for k, v := range appState.Notes {
+		loadedNotes[reader.BookID(k)] = v
 	}
 
-	program := tea.NewProgram(model, tea.WithOutput(os.Stdout))
+	model := ui.NewModelWithConfig(cfg, initialBook, loadedBookmarks, loadedPositions)
+	model.SetUnifiedReader(unified)
+	model.SetNotes(loadedNotes)
+	model.SetRecentFiles(appState.RecentFiles)
+	model.SetLibrary(appState.Library)
+	model.SetDailyProgress(appState.DailyProgress)
+	model.SetConfigPath(paths.ConfigFile)
+	model.SetAcceptDrops(*acceptDrops)
+	model.RestoreTabs(tabs, activeTab)
+	model.DetectColorScheme()
+
+	opts := []tea.ProgramOption{tea.WithOutput(os.Stdout), tea.WithMouseCellMotion()}
+	if fromStdin {
+		opts = append(opts, tea.WithInputTTY())
+	}
+	program := tea.NewProgram(model, opts...)
+
+	// Re-read the config file on SIGHUP so settings can be changed
+	// without restarting. Windows never delivers SIGHUP, which simply
+	// leaves the watcher idle there.
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			cfg, err := config.Load(paths.ConfigFile)
+			program.Send(ui.ConfigReloadMsg{Config: cfg, Err: err})
+		}
+	}()
 
 	finalModel, err := program.Run()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// On normal exit, persist updated bookmarks (and leave room for
-	// positions/recent files when those are wired in).
+	// On normal exit, persist updated bookmarks, reading positions, and
+	// recent files.
 	if m, ok := finalModel.(ui.Model); ok {
 		bookmarks := m.ExportBookmarks()
 		appState.Bookmarks = make(map[string][]reader.Bookmark)
 		for k, v := range bookmarks {
 			appState.Bookmarks[string(k)] = v
 		}
+		appState.Notes = make(map[string][]reader.Note)
+		for k, v := range m.ExportNotes() {
+			appState.Notes[string(k)] = v
+		}
+		appState.Positions = make(map[string]reader.Position)
+		for k, v := range m.ExportPositions() {
+			appState.Positions[string(k)] = v
+		}
+		appState.RecentFiles = m.ExportRecentFiles()
+		appState.OpenBookPaths = m.ExportOpenBookPaths()
+		appState.Library = m.ExportLibrary()
+		appState.DailyProgress = m.ExportDailyProgress()
+		// With no book open, nothing is reopened on the next start.
+		if book := m.CurrentBook(); book == nil {
+			appState.LastOpenedBookPath = ""
+		} else if book.SourcePath != "" {
+			appState.LastOpenedBookPath = book.SourcePath
+		}
 		if err := store.Save(appState); err != nil {
 			log.Printf("warning: failed to save state: %v", err)
 		}
 	}
 }
+
+// dumpText opens the book at bookArg with unified, or reads it from
+// standard input when bookArg is "-" or empty, and writes its text to
+// output, or to standard output when output is "-".
+func dumpText(unified reader.UnifiedReader, bookArg, output string) error {
+	var book reader.LoadedBook
+	var err error
+	if bookArg == "" || bookArg == "-" {
+		book, err = unified.OpenStream(os.Stdin, "stdin")
+	} else {
+		book, err = unified.Open(bookArg)
+	}
+	if err != nil {
+		return err
+	}
+	text := book.Text
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if output == "-" {
+		_, err = io.WriteString(os.Stdout, text)
+		return err
+	}
+	return os.WriteFile(output, []byte(text), 0o644)
+}
@@ -1,3 +1,33 @@
+// Package config loads and saves the user-editable settings. They are
+// kept in config.json or, for users who prefer it, config.toml; both
+// hold the same settings under the same names. A TOML config looks
+// like this (every setting is optional and defaults as documented on
+// Config):
+//
+//	theme_override = "light"       # "dark", "light", "mono", "high_contrast",
+//	                               # "solarized-dark" or "solarized-light"
+//	recent_list_size = 10
+//	default_library_path = "~/Books"
+//	tab_completion_enabled = true
+//	input_history_size = 20
+//	g_key = "goto_percent"         # or "end"
+//	language = "en"                # BCP-47 tag
+//	auto_theme = true
+//	bookshelf_scan_depth = 1
+//	word_wrap = true
+//	scroll_lines = 3
+//	tab_width = 4
+//	auto_scroll_delay = 3000       # milliseconds per line
+//	allow_delete_toc = false       # allow deleting bookmarks made from the TOC
+//	left_margin = 0                # blank columns beside the text
+//	right_margin = 0
+//
+//	[reading_goals]
+//	daily_chars_target = 5000      # 0 turns the goal off
+//
+//	[keybindings]                  # command name = key
+//	find = "ctrl+f"
+//	toc = "t"
 package config
 
 import (
@@ -6,6 +36,11 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"thujareader/internal/atomicfile"
 )
 
 // Config holds user-editable settings loaded from a JSON file. The
@@ -13,25 +48,120 @@ import (
 // later phases without breaking existing configs (unknown fields are
 // ignored on load).
 type Config struct {
-	// ThemeOverride allows selecting an alternate theme if supported by
-	// the UI. For now this is a free-form string.
-	ThemeOverride string `json:"theme_override,omitempty"`
+	// ThemeOverride selects a theme by name: "dark", "light", "mono",
+	// "high_contrast", "solarized-dark" or "solarized-light". Empty or
+	// unknown names leave the choice to the environment and AutoTheme.
+	ThemeOverride string `json:"theme_override,omitempty" toml:"theme_override,omitempty"`
 
 	// RecentListSize limits the number of recent files remembered. If
 	// zero or negative, a sensible default is used.
-	RecentListSize int `json:"recent_list_size,omitempty"`
+	RecentListSize int `json:"recent_list_size,omitempty" toml:"recent_list_size,omitempty"`
 
 	// DefaultLibraryPath, when set, can be used as a starting directory
 	// for file-open dialogs or path prompts.
-	DefaultLibraryPath string `json:"default_library_path,omitempty"`
+	DefaultLibraryPath string `json:"default_library_path,omitempty" toml:"default_library_path,omitempty"`
+
+	// TabCompletionEnabled turns on Tab completion of file paths in the
+	// Open prompt. It has no omitempty so that an explicit false
+	// survives a save/load round trip.
+	TabCompletionEnabled bool `json:"tab_completion_enabled" toml:"tab_completion_enabled"`
+
+	// InputHistorySize limits how many previous entries are remembered
+	// per input prompt (Open, Find, ...). If zero or negative, a
+	// sensible default is used.
+	InputHistorySize int `json:"input_history_size,omitempty" toml:"input_history_size,omitempty"`
+
+	// GKey decides what "G" does in reading mode, since two common
+	// conventions claim it: GKeyGotoPercent (the default) opens the
+	// Goto % prompt, GKeyEnd jumps to the end of the book as in Vim.
+	GKey string `json:"g_key,omitempty" toml:"g_key,omitempty"`
+
+	// Language selects the UI language as a BCP-47 tag (e.g. "en",
+	// "de-CH"). An empty or unrecognized tag falls back to English.
+	Language string `json:"language,omitempty" toml:"language,omitempty"`
+
+	// AutoTheme lets the UI query the terminal's background color at
+	// startup and pick a light or dark theme to match when
+	// ThemeOverride is empty. It has no omitempty so that an explicit
+	// false survives a save/load round trip.
+	AutoTheme bool `json:"auto_theme" toml:"auto_theme"`
+
+	// BookshelfScanDepth limits how many directory levels below a
+	// library directory are searched for books: 0 means only the
+	// directory itself, 1 includes its immediate subdirectories. It has
+	// no omitempty because 0 is a meaningful value.
+	BookshelfScanDepth int `json:"bookshelf_scan_depth" toml:"bookshelf_scan_depth"`
+
+	// WordWrap breaks lines at spaces so words are not split across
+	// lines; when false, lines are cut at the window width. It has no
+	// omitempty so that an explicit false survives a save/load round
+	// trip.
+	WordWrap bool `json:"word_wrap" toml:"word_wrap"`
+
+	// ScrollLines is how many lines one mouse-wheel step scrolls. If
+	// zero or negative, a sensible default is used.
+	ScrollLines int `json:"scroll_lines,omitempty" toml:"scroll_lines,omitempty"`
+
+	// TabWidth is the distance between tab stops when rendering tab
+	// characters. If zero or negative, a sensible default is used.
+	TabWidth int `json:"tab_width,omitempty" toml:"tab_width,omitempty"`
+
+	// AutoScrollDelay is how many milliseconds auto-scroll waits before
+	// advancing another line. If zero or negative, a sensible default is
+	// used.
+	AutoScrollDelay int `json:"auto_scroll_delay,omitempty" toml:"auto_scroll_delay,omitempty"`
+
+	// AllowDeleteTOC lets the bookmarks dialog delete the bookmarks
+	// created from a book's table of contents, which are kept otherwise.
+	AllowDeleteTOC bool `json:"allow_delete_toc,omitempty" toml:"allow_delete_toc,omitempty"`
+
+	// LeftMargin and RightMargin are how many blank columns to keep
+	// between the text and the left and right border. They are dropped
+	// in windows too narrow for them; negative values count as zero.
+	LeftMargin  int `json:"left_margin,omitempty" toml:"left_margin,omitempty"`
+	RightMargin int `json:"right_margin,omitempty" toml:"right_margin,omitempty"`
+
+	// ReadingGoals sets targets the status bar tracks reading progress
+	// against.
+	ReadingGoals ReadingGoals `json:"reading_goals" toml:"reading_goals"`
+
+	// Keybindings overrides the keys of commands, mapping command names
+	// to keys in the form shown in the menus, e.g. {"find": "ctrl+f",
+	// "toc": "t"}. Letter keys only work while reading; other keys work
+	// everywhere. Unknown command names are ignored.
+	Keybindings map[string]string `json:"keybindings,omitempty" toml:"keybindings,omitempty"`
+}
+
+// ReadingGoals holds the reading targets of Config.ReadingGoals.
+type ReadingGoals struct {
+	// DailyCharsTarget is how many characters the user aims to read per
+	// day. Zero or negative turns the goal off.
+	DailyCharsTarget int `json:"daily_chars_target,omitempty" toml:"daily_chars_target,omitempty"`
 }
 
+// Values accepted for Config.GKey.
+const (
+	GKeyGotoPercent = "goto_percent"
+	GKeyEnd         = "end"
+)
+
 // DefaultConfig returns a Config populated with built-in defaults.
 func DefaultConfig() Config {
 	return Config{
 		ThemeOverride:      "",
 		RecentListSize:     10,
 		DefaultLibraryPath: "",
+
+		TabCompletionEnabled: true,
+		InputHistorySize:     20,
+		GKey:                 GKeyGotoPercent,
+		Language:             "en",
+		AutoTheme:            true,
+		BookshelfScanDepth:   1,
+		WordWrap:             true,
+		ScrollLines:          3,
+		TabWidth:             4,
+		AutoScrollDelay:      3000,
 	}
 }
 
@@ -42,44 +172,93 @@ type Paths struct {
 	StateFile  string
 }
 
-// DefaultPaths computes per-user paths for the config and state JSON
-// files. On Windows it uses %APPDATA%\thujareader; on Unix-like systems
-// it uses $XDG_CONFIG_HOME/thujareader or ~/.config/thujareader.
+// DefaultPaths computes per-user paths for the config and state files.
+// On Windows the config lives in the roaming profile
+// (%APPDATA%\thujareader) so settings follow the user across machines,
+// while state (reading positions, recent files) is machine-specific and
+// lives in %LOCALAPPDATA%\thujareader. On Unix-like systems both use
+// $XDG_CONFIG_HOME/thujareader or ~/.config/thujareader. Everywhere the
+// config file is config.json, or config.toml when only that one exists.
 func DefaultPaths() (Paths, error) {
-	var base string
 	if runtime.GOOS == "windows" {
-		base = os.Getenv("APPDATA")
-		if base == "" {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return Paths{}, err
-			}
-			base = filepath.Join(home, "AppData", "Roaming")
+		configBase, err := windowsAppDir("APPDATA", "Roaming")
+		if err != nil {
+			return Paths{}, err
 		}
-		base = filepath.Join(base, "thujareader")
-	} else {
-		base = os.Getenv("XDG_CONFIG_HOME")
-		if base == "" {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return Paths{}, err
-			}
-			base = filepath.Join(home, ".config")
+		stateBase, err := windowsAppDir("LOCALAPPDATA", "Local")
+		if err != nil {
+			return Paths{}, err
 		}
-		base = filepath.Join(base, "thujareader")
+		return Paths{
+			ConfigFile: configFileIn(configBase),
+			StateFile:  filepath.Join(stateBase, "state.json"),
+		}, nil
 	}
 
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Paths{}, err
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return PathsInDir(filepath.Join(base, "thujareader")), nil
+}
+
+// windowsAppDir returns the thujareader directory under the folder named
+// by envVar, falling back to %USERPROFILE%\AppData\<fallback> when the
+// variable is unset.
+func windowsAppDir(envVar, fallback string) (string, error) {
+	base := os.Getenv(envVar)
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, "AppData", fallback)
+	}
+	return filepath.Join(base, "thujareader"), nil
+}
+
+// PathsInDir returns the config and state file locations inside a
+// single data directory. It backs both DefaultPaths and the
+// --data-dir override used for portable installs (e.g. a USB drive).
+// The config file is config.json, or config.toml when only that one
+// exists.
+func PathsInDir(dir string) Paths {
 	return Paths{
-		ConfigFile: filepath.Join(base, "config.json"),
-		StateFile:  filepath.Join(base, "state.json"),
-	}, nil
+		ConfigFile: configFileIn(dir),
+		StateFile:  filepath.Join(dir, "state.json"),
+	}
+}
+
+// configFileIn returns the config file in dir: config.json, or
+// config.toml when only that one exists.
+func configFileIn(dir string) string {
+	configFile := filepath.Join(dir, "config.json")
+	if _, err := os.Stat(configFile); os.IsNotExist(err) {
+		if _, err := os.Stat(filepath.Join(dir, "config.toml")); err == nil {
+			configFile = filepath.Join(dir, "config.toml")
+		}
+	}
+	return configFile
+}
+
+// isTOML reports whether path names a TOML config file.
+func isTOML(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
 }
 
-// Load reads configuration from the given path. If the file does not
-// exist, DefaultConfig is returned with a nil error. If the file is
-// present but invalid, a non-nil error is returned so callers can
-// decide how to proceed.
+// Load reads configuration from the given path, as TOML when it ends in
+// .toml and as JSON otherwise. If the file does not exist,
+// DefaultConfig is returned with a nil error. If the file is present
+// but invalid, a non-nil error is returned so callers can decide how
+// to proceed.
 func Load(path string) (Config, error) {
+	if isTOML(path) {
+		return LoadTOML(path)
+	}
 	if path == "" {
 		return DefaultConfig(), errors.New("config path is empty")
 	}
@@ -102,8 +281,35 @@ func Load(path string) (Config, error) {
 	return cfg, nil
 }
 
-// Save writes the provided configuration to disk as JSON, creating the
-// parent directory if needed.
+// LoadTOML reads configuration from a TOML file, with the same
+// handling of missing, empty and invalid files as Load.
+func LoadTOML(path string) (Config, error) {
+	if path == "" {
+		return DefaultConfig(), errors.New("config path is empty")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultConfig(), nil
+		}
+		return DefaultConfig(), err
+	}
+	if len(data) == 0 {
+		return DefaultConfig(), nil
+	}
+
+	cfg := DefaultConfig()
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return DefaultConfig(), err
+	}
+	return cfg, nil
+}
+
+// Save writes the provided configuration to disk, as TOML when path
+// ends in .toml and as JSON otherwise, creating the parent directory if
+// needed. The file is replaced atomically, so a crash while saving
+// leaves the previous configuration intact.
 func Save(path string, cfg Config) error {
 	if path == "" {
 		return errors.New("config path is empty")
@@ -114,9 +320,15 @@ func Save(path string, cfg Config) error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	var data []byte
+	var err error
+	if isTOML(path) {
+		data, err = toml.Marshal(cfg)
+	} else {
+		data, err = json.MarshalIndent(cfg, "", "  ")
+	}
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o644)
+	return atomicfile.WriteFile(path, data, 0o644)
 }
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// touch creates an empty file at path.
+func touch(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigFileIn(t *testing.T) {
+	dir := t.TempDir()
+	if got, want := configFileIn(dir), filepath.Join(dir, "config.json"); got != want {
+		t.Errorf("no config file: got %s, want %s", got, want)
+	}
+	touch(t, filepath.Join(dir, "config.toml"))
+	if got, want := configFileIn(dir), filepath.Join(dir, "config.toml"); got != want {
+		t.Errorf("only config.toml: got %s, want %s", got, want)
+	}
+	touch(t, filepath.Join(dir, "config.json"))
+	if got, want := configFileIn(dir), filepath.Join(dir, "config.json"); got != want {
+		t.Errorf("both files: got %s, want %s", got, want)
+	}
+}
+
+func TestDefaultPathsFindsTOML(t *testing.T) {
+	base := t.TempDir()
+	var dir string
+	if runtime.GOOS == "windows" {
+		t.Setenv("APPDATA", filepath.Join(base, "Roaming"))
+		t.Setenv("LOCALAPPDATA", filepath.Join(base, "Local"))
+		dir = filepath.Join(base, "Roaming", "thujareader")
+	} else {
+		t.Setenv("XDG_CONFIG_HOME", base)
+		dir = filepath.Join(base, "thujareader")
+	}
+	touch(t, filepath.Join(dir, "config.toml"))
+	paths, err := DefaultPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "config.toml"); paths.ConfigFile != want {
+		t.Errorf("ConfigFile = %s, want %s", paths.ConfigFile, want)
+	}
+}
@@ -0,0 +1,255 @@
+// Package i18n collects the user-visible strings of the TUI in one
+// place so they can be translated without touching the UI logic.
+//
+// Each constant is a message key in golang.org/x/text/message form: the
+// English text doubles as the key and as a format string. Translations
+// are registered per language with message.SetString; languages without
+// a catalog entry fall back to the English key.
+package i18n
+
+import (
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Menu bar and menu item labels.
+const (
+	MenuFile      = "File"
+	MenuSearch    = "Search"
+	MenuView      = "View"
+	MenuBookmarks = "Bookmarks"
+	MenuHelp      = "Help"
+
+	MenuItemOpen             = "Open..."
+	MenuItemOpenClipboard    = "Open from Clipboard"
+	MenuItemReload           = "Reload"
+	MenuItemNewTab           = "Open in New Tab..."
+	MenuItemCloseTab         = "Close Tab"
+	MenuItemRecentFiles      = "Recent Files"
+	MenuItemClearRecentFiles = "Clear Recent Files"
+	MenuItemExportText       = "Export as Text..."
+	MenuItemExit             = "Exit"
+	MenuItemFind             = "Find..."
+	MenuItemFindPrevious     = "Find Previous"
+	MenuItemTOC              = "TOC"
+	MenuItemMatchAcrossLines = "Match Across Lines"
+	MenuItemGotoPercent      = "Goto Percent..."
+	MenuItemGotoChapter      = "Goto Chapter..."
+	MenuItemGotoLine         = "Goto Line..."
+	MenuItemSearchMode       = "Search Mode"
+	MenuItemWordWrap         = "Word Wrap"
+	MenuItemZoomIn           = "Increase Font Size"
+	MenuItemZoomOut          = "Decrease Font Size"
+	MenuItemZoomReset        = "Reset Font Size"
+	MenuItemNextTab          = "Next Tab"
+	MenuItemSelectText       = "Select Text"
+	MenuItemPrevTab          = "Previous Tab"
+	MenuItemManageBookmarks  = "Manage Bookmarks"
+	MenuItemAddBookmark      = "Add Bookmark"
+	MenuItemDeleteBookmark   = "Delete Bookmark"
+	MenuItemRenameBookmark   = "Rename Bookmark"
+	MenuItemExportBookmarks  = "Export Bookmarks..."
+	MenuItemAddNote          = "Add Note..."
+	MenuItemManageNotes      = "Manage Notes"
+	MenuItemHelpTopics       = "Help Topics"
+	MenuItemStats            = "Book Statistics"
+	MenuItemMetadata         = "Book Information"
+	MenuItemLookup           = "Look Up Word"
+	MenuItemAutoScroll       = "Auto-scroll"
+	MenuItemSettings         = "Settings..."
+	MenuItemSplitView        = "Split View"
+	MenuItemFootnote         = "Show Footnote"
+)
+
+// Prompts and dialog labels.
+const (
+	PromptOpen            = "Open file: "
+	PromptFind            = "Find: "
+	PromptRenameBookmark  = "Rename bookmark: "
+	PromptConfigValue     = "%s: "
+	PromptExportBookmarks = "Export bookmarks to (.md or .json): "
+	PromptExportText      = "Export text to: "
+	PromptGotoPercent     = "Goto %%: "
+	PromptGotoChapter     = "Goto chapter (1–%d): "
+	PromptGotoLine        = "Goto line (0–%d): "
+	PromptClearRecent     = "Clear all recent files? [y/N]"
+	LabelInitializing     = "thujareader – initializing..."
+	LabelTerminalTooSmall = "Terminal too small for thujareader UI. Resize the window."
+	LabelLoading          = "Loading: %s…"
+	LabelPage             = "Page %d/%d"
+	LabelChapter          = "Chapter %d"
+	LabelPercent          = "%d%%"
+	LabelUnknownPercent   = "(unknown %%)"
+	LabelTimeLeft         = "~%d min left"
+	LabelBookmarkName     = "Bookmark %d"
+	LabelTOCBookmark      = "[T] %s"
+	LabelNoMatches        = "  No matches for: %s  "
+	LabelBrowserEmpty     = "(no books or folders here)"
+	LabelHelpReading      = "Reading"
+	LabelNoteEditor       = "Note:"
+	LabelStatsCharacters  = "Characters: %d"
+	LabelStatsWords       = "Words: %d"
+	LabelStatsReadingTime = "Reading time: %d h %d min at %d words per minute"
+	LabelStatsChapters    = "Chapters: %d"
+	LabelStatsBookmarks   = "Bookmarks: %d"
+	LabelMetadataAuthor   = "author"
+	LabelMetadataNone     = "The book has no further information."
+	LabelShelfTitle       = "Title"
+	LabelShelfAuthor      = "Author"
+	LabelShelfProgress    = "Progress"
+	LabelShelfLastOpened  = "Last opened"
+	LabelSearchExact      = "exact"
+	LabelSearchIgnoreCase = "ignore case"
+	LabelSearchRegex      = "regex"
+	LabelSearchModeTag    = "[%s]"
+	LabelLookupTitle      = "%s (%s)"
+	LabelAutoScrollOn     = "Auto-scroll ON"
+	LabelDailyGoal        = "Today: %d / %d chars"
+	LabelConfigSave       = " [ Save ]"
+	LabelDropHere         = "Drop a book file here to open it"
+	LabelTOCPaneEmpty     = "No contents"
+	LabelFootnoteTitle    = "Note %s"
+)
+
+// Status bar messages.
+const (
+	MsgWelcome              = "Press F10 or Alt key combinations to open menus. F1 for Help."
+	MsgConfigReloadFailed   = "Config reload failed: %v"
+	MsgConfigReloaded       = "Configuration reloaded."
+	MsgOpenFailed           = "Failed to open: %v"
+	MsgBookOpened           = "Opened: %s"
+	MsgBookOpenedViaSymlink = "Opened (symlink → %s): %s"
+	MsgBookWarnings         = "%s (warning: %s)"
+	MsgBookInvalid          = "Warning: %s: %v"
+	MsgJumpedToBookmark     = "Jumped to bookmark: %s"
+	MsgOpenHint             = "Enter path to an EPUB, FB2, MOBI or TXT file (or a ZIP of one) and press Enter."
+	MsgBrowserHint          = "Open: ↑/↓ select, Enter open, Backspace parent folder, Tab type a path, Esc cancel."
+	MsgBrowserFailed        = "Open: cannot list folder: %v"
+	MsgExitHint             = "Exit: press Alt+F then X or Ctrl+C to quit."
+	MsgFindHint             = "Enter search text and press Enter. Press Esc to cancel."
+	MsgTOCEmpty             = "TOC: no table of contents available for this book."
+	MsgTOCHint              = "TOC: Use ↑/↓ to select, Enter to jump, Esc to cancel."
+	MsgBookmarksNoBook      = "Bookmarks: no book is currently open."
+	MsgBookmarksEmpty       = "Bookmarks: no bookmarks for this book."
+	MsgBookmarksHint        = "Bookmarks: Use ↑/↓ to select, Enter to jump, R to rename, Esc to cancel."
+	MsgBookmarkNoBook       = "Cannot add bookmark: no book is open."
+	MsgBookmarkAdded        = "Added bookmark: %s"
+	MsgBookmarkDeleted      = "Deleted bookmark: %s"
+	MsgBookmarkTOCLocked    = "Bookmarks from the table of contents cannot be deleted; set allow_delete_toc in the config to allow it."
+	MsgBookmarkRenamed      = "Renamed bookmark to: %s"
+	MsgBookmarkNameEmpty    = "Bookmarks: name must not be empty."
+	MsgRecentEmpty          = "Recent files: list is empty."
+	MsgRecentHint           = "Recent files: Use ↑/↓ to select, Enter to open, Esc to cancel."
+	MsgRecentCleared        = "Recent files cleared."
+	MsgRecentRemoved        = "Removed from recent files: %s"
+	MsgRecentRemovedLast    = "Removed from recent files: %s (list is now empty)."
+	MsgFindNormalizeOn      = "Find: whitespace-normalized matching on (terms match across lines)."
+	MsgFindNormalizeOff     = "Find: whitespace-normalized matching off (exact matching)."
+	MsgFindEmpty            = "Find: empty search term."
+	MsgFindNoMatches        = "Find: no matches."
+	MsgFindNoMore           = "Find: no more matches."
+	MsgFindMatch            = "Find: match %d of %d."
+	MsgFindNoEarlier        = "Find: no earlier matches."
+	MsgFindMode             = "Find: search mode is now %s."
+	MsgWordWrapOn           = "Word wrap on: lines break between words."
+	MsgWordWrapOff          = "Word wrap off: lines break at the window edge."
+	MsgFindBadRegex         = "Find: invalid regular expression: %v"
+	MsgGotoNoBook           = "Goto: no book is currently open."
+	MsgGotoHint             = "Enter a percentage (0–100) and press Enter."
+	MsgGotoInvalid          = "Goto: enter a percentage between 0 and 100."
+	MsgJumpedToPercent      = "Jumped to %d%%"
+	MsgGotoChapterHint      = "Enter a chapter number and press Enter."
+	MsgGotoChapterNone      = "Goto chapter: this book has no chapters."
+	MsgGotoChapterInvalid   = "Goto chapter: enter a number between 1 and %d."
+	MsgJumpedToChapter      = "Jumped to chapter %d: %s"
+	MsgJumpedToChapterN     = "Jumped to chapter %d"
+	MsgGotoLineHint         = "Enter a line number and press Enter."
+	MsgGotoLineInvalid      = "Goto line: enter a number between 0 and %d."
+	MsgGotoLineOutOfRange   = "Line %d out of range"
+	MsgJumpedToLine         = "Line %d / %d"
+	MsgHelpHint             = "Help: ↑/↓ and PgUp/PgDn scroll, Esc or F1 closes."
+	MsgCancelled            = "Cancelled."
+	MsgNoPath               = "No file path provided."
+	MsgUnsupportedFile      = "File does not appear to be a supported book format."
+	MsgReloadNoBook         = "Reload: no book is currently open."
+	MsgBookReloaded         = "Reloaded: %s"
+	MsgPositionClamped      = "%s (saved position no longer exists; moved to the nearest one)"
+	MsgClipboardReading     = "Reading clipboard…"
+	MsgClipboardFailed      = "Clipboard: %v"
+	MsgClipboardEmpty       = "Clipboard: no path or URL to open."
+	MsgTabsSingle           = "Tabs: only one book is open."
+	MsgTabSwitched          = "Tab %d/%d: %s"
+	MsgTabClosed            = "Closed tab: %s"
+	MsgSelectHint           = "Select: arrows extend the selection, Enter or Y copies, Esc cancels."
+	MsgSelectNoBook         = "Select: no book is currently open."
+	MsgCopied               = "Copied %d characters"
+	MsgZoom                 = "Zoom %d: text is %d columns wide."
+	MsgBookmarksExported    = "Exported %d bookmarks to %s"
+	MsgStatsNoBook          = "Statistics: no book is currently open."
+	MsgStatsHint            = "Statistics: press Esc to close."
+	MsgMetadataNoBook       = "Book information: no book is currently open."
+	MsgMetadataHint         = "Book information: press Esc to close."
+	MsgNoteNoBook           = "Cannot add note: no book is open."
+	MsgNotesNoBook          = "Notes: no book is currently open."
+	MsgNotesEmpty           = "Notes: no notes for this book."
+	MsgNotesHint            = "Notes: Use ↑/↓ to select, Enter to jump, E to edit, D to delete, Esc to cancel."
+	MsgNoteHint             = "Note: Enter starts a new line, Ctrl+S saves, Esc cancels."
+	MsgNoteSaved            = "Note saved."
+	MsgNoteDeleted          = "Note deleted."
+	MsgNoteEmpty            = "Note is empty; nothing saved."
+	MsgExportFailed         = "Export failed: %v"
+	MsgExportTextNoBook     = "Export: no book is currently open."
+	MsgExportTextBusy       = "Export: the previous export is still being written."
+	MsgExportingText        = "Exporting text… %d%%"
+	MsgTextExported         = "Exported the text to %s"
+	MsgCursorHint           = "Cursor: arrows move, D looks up the word, F shows a footnote, Esc or Enter leaves cursor mode."
+	MsgCursorOff            = "Cursor mode off."
+	MsgLookupNoBook         = "Look up: no book is currently open."
+	MsgLookupPickWord       = "Look up: move the cursor to a word and press D again."
+	MsgLookupNoWord         = "Look up: the cursor is not on a word."
+	MsgLookupPending        = "Looking up “%s”…"
+	MsgLookupNotFound       = "Dictionary: no definition found for “%s”."
+	MsgLookupFailed         = "Dictionary: %v"
+	MsgPopupHint            = "Press any key to close."
+	MsgFootnoteNoBook       = "Footnote: no book is currently open."
+	MsgFootnotesNone        = "Footnote: this book has no footnotes."
+	MsgFootnotePick         = "Footnote: move the cursor to a note marker and press F again."
+	MsgFootnoteNotMarker    = "Footnote: the cursor is not on a note marker."
+	MsgFootnoteMissing      = "Footnote: note %s is missing from the book."
+	MsgAutoScrollNoBook     = "Auto-scroll: no book is currently open."
+	MsgAutoScrollOn         = "Auto-scroll ON: press A or an arrow key to stop."
+	MsgAutoScrollOff        = "Auto-scroll off."
+	MsgAutoScrollEnd        = "Auto-scroll off: reached the end of the book."
+	MsgConfigHint           = "Settings: Use ↑/↓ to select, Enter to edit, S to save, Esc to cancel."
+	MsgConfigInvalid        = "Settings: %v"
+	MsgConfigSaved          = "Settings saved to %s"
+	MsgConfigSaveFailed     = "Settings: cannot save: %v"
+	MsgDropHint             = "Waiting for a book: drop a file onto this window, or press F10 for the menus."
+	MsgSplitViewOn          = "Split view on: the table of contents follows your position."
+	MsgSplitViewOff         = "Split view off."
+	MsgSplitViewNoRoom      = "Split view: the window is too narrow for the contents pane."
+	MsgDailyGoalReached     = "Daily goal reached: %d characters read today."
+)
+
+// Help screen descriptions of keys that have no menu item.
+const (
+	HelpScrollLine     = "Scroll one line"
+	HelpScrollPage     = "Scroll one page"
+	HelpScrollHalfPage = "Scroll half a page"
+	HelpStartEnd       = "Go to the start or end of the book"
+	HelpCursor         = "Show a movable cursor, e.g. to look up a word"
+	HelpFindNext       = "Find the next match of the last search"
+	HelpMenuBar        = "Open the menu bar"
+	HelpAltMenu        = "Open the File, Search, ... menu"
+	HelpClose          = "Close the open menu or dialog"
+)
+
+// NewPrinter returns a printer for the given BCP-47 language tag. An
+// empty or malformed tag selects English.
+func NewPrinter(lang string) *message.Printer {
+	tag, err := language.Parse(lang)
+	if lang == "" || err != nil {
+		tag = language.English
+	}
+	return message.NewPrinter(tag)
+}
@@ -12,10 +12,12 @@ type Position struct {
 
 // Chapter models a logical chapter or section within a book.
 type Chapter struct {
-	Index  int
+	Index int
+	// Title has no leading or trailing whitespace; an empty title means
+	// the chapter is untitled.
 	Title  string
-	Offset int // Start offset of the chapter within the linearized text stream.
-	Length int // Length of the chapter in runes or characters.
+	Offset int // Start offset of the chapter within the linearized text stream, in runes.
+	Length int // Length of the chapter in runes; chapters tile the text.
 }
 
 // Book represents a logical book with metadata and an ordered list
@@ -26,8 +28,14 @@ type Book struct {
 	Author   string
 	Chapters []Chapter
 
+	// Metadata holds further descriptive fields the format provides,
+	// such as "publisher", "language" or "description", keyed by
+	// lower-case name. Formats without them leave it nil.
+	Metadata map[string]string
+
 	// TotalCharacters is an optional aggregate aiding in percentage
-	// calculations for navigation and progress display.
+	// calculations for navigation and progress display. Zero is a valid
+	// value meaning "size unknown"; progress is then shown as unknown.
 	TotalCharacters int
 }
 
@@ -42,6 +50,10 @@ type Bookmark struct {
 	Name   string
 	BookID BookID
 	Pos    Position
+
+	// FromTOC marks bookmarks created from the book's table of
+	// contents rather than by the user.
+	FromTOC bool `json:",omitempty"`
 }
 
 // GetPosition returns the position associated with the bookmark.
@@ -49,6 +61,19 @@ func (b Bookmark) GetPosition() Position {
 	return b.Pos
 }
 
+// Note is a piece of text the reader attached to a location within a
+// specific book.
+type Note struct {
+	BookID BookID
+	Pos    Position
+	Text   string
+}
+
+// GetPosition returns the position associated with the note.
+func (n Note) GetPosition() Position {
+	return n.Pos
+}
+
 // TOCEntry represents an entry in the book's table of contents.
 type TOCEntry struct {
 	Label  string
@@ -60,3 +85,96 @@ type TOCEntry struct {
 func (e TOCEntry) GetPosition() Position {
 	return e.Pos
 }
+
+// LoadedBook is the unified result of opening a book file: the book's
+// metadata and chapter layout, its text as a single linear stream, and
+// its table of contents.
+type LoadedBook struct {
+	Book Book
+
+	// Text is the linearized book text. Chapter offsets and lengths, as
+	// well as positions, are measured in runes of this string.
+	//
+	// Text is always valid UTF-8: loaders must decode or sanitize their
+	// input (see utf8.Valid) before returning, since invalid bytes would
+	// turn into U+FFFD when converted to runes and silently shift every
+	// offset. UnifiedReader.Open enforces this with ValidateLoadedBook.
+	Text string
+
+	TOC []TOCEntry
+
+	// SourcePath is the file the book was loaded from, as passed to
+	// UnifiedReader.Open.
+	SourcePath string
+
+	// CoverImageData holds the raw cover image (e.g. the EPUB item with
+	// properties="cover-image" or the FB2 <binary> referenced by the
+	// cover page) and CoverImageMIME its media type. Formats without
+	// covers leave both empty; len(CoverImageData) == 0 means "no cover".
+	CoverImageData []byte
+	CoverImageMIME string
+
+	// Stats summarizes the text. UnifiedReader.Open computes it, so it
+	// is at hand without another pass over the text.
+	Stats BookStats
+
+	// Footnotes maps note IDs to the text of the book's footnotes, and
+	// FootnoteRefs lists the markers in Text that refer to them, in text
+	// order. Formats without footnotes leave both empty.
+	Footnotes    map[string]string
+	FootnoteRefs []FootnoteRef
+
+	// Spans marks runs of Text that are styled differently, such as the
+	// contents of <code> and <pre> elements, in text order. Formats
+	// without such markup leave it empty.
+	Spans []Span
+}
+
+// Span is a run of Length runes at rune offset Offset of
+// LoadedBook.Text. CodeBlock is set for program code, which readers may
+// show in a different color.
+type Span struct {
+	Offset    int
+	Length    int
+	CodeBlock bool
+}
+
+// FootnoteRef is a footnote marker, such as "[1]", in the book text:
+// Length runes at rune offset Offset of LoadedBook.Text, referring to
+// the note with the given ID.
+type FootnoteRef struct {
+	Offset int
+	Length int
+	ID     string
+}
+
+// HasCover reports whether the book carries a cover image.
+func (b LoadedBook) HasCover() bool {
+	return len(b.CoverImageData) > 0
+}
+
+// Reader is implemented by format-specific loaders (EPUB, FB2, ...).
+// UnifiedReader dispatches to the Reader whose Extensions include the
+// file's extension.
+type Reader interface {
+	// Extensions lists the lower-case file extensions the reader
+	// handles, including the leading dot (e.g. ".epub").
+	Extensions() []string
+
+	// Open loads and normalizes the book at path.
+	Open(path string) (LoadedBook, error)
+}
+
+// ReaderPlugin is a loader added at run time with
+// UnifiedReader.RegisterPlugin. Unlike Reader it decides by itself
+// which files it handles, e.g. by sniffing content rather than by
+// extension. A plugin that also has an Extensions() []string method
+// contributes those extensions to UnifiedReader.Extensions, so its
+// files show up in the file browser.
+type ReaderPlugin interface {
+	// CanOpen reports whether the plugin handles the file at path.
+	CanOpen(path string) bool
+
+	// Open loads the book at path.
+	Open(path string) (LoadedBook, error)
+}
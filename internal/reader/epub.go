@@ -0,0 +1,766 @@
+package reader
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// EPUBReader loads EPUB 2 and 3 files. Every spine item becomes a
+// chapter, in spine order; items without text (such as cover pages) are
+// skipped. The table of contents comes from the EPUB 3 navigation
+// document or, failing that, the EPUB 2 NCX, with links into the middle
+// of a chapter resolved to the anchor's offset. Encrypted books are
+// rejected with ErrEncrypted.
+type EPUBReader struct{}
+
+// NewEPUBReader returns a reader for .epub files.
+func NewEPUBReader() EPUBReader {
+	return EPUBReader{}
+}
+
+// Extensions implements Reader.
+func (EPUBReader) Extensions() []string {
+	return []string{".epub"}
+}
+
+// opfPackage is the part of the OPF package document the reader uses.
+type opfPackage struct {
+	UniqueID string `xml:"unique-identifier,attr"`
+	Metadata struct {
+		Titles      []string `xml:"title"`
+		Creators    []string `xml:"creator"`
+		Identifiers []struct {
+			ID    string `xml:"id,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"identifier"`
+		Metas []struct {
+			Name    string `xml:"name,attr"`
+			Content string `xml:"content,attr"`
+		} `xml:"meta"`
+		// Others collects the remaining elements, such as
+		// dc:publisher and dc:language.
+		Others []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"metadata"`
+	Manifest []opfItem `xml:"manifest>item"`
+	Spine    struct {
+		TOC      string `xml:"toc,attr"`
+		ItemRefs []struct {
+			IDRef  string `xml:"idref,attr"`
+			Linear string `xml:"linear,attr"`
+		} `xml:"itemref"`
+	} `xml:"spine"`
+}
+
+// opfItem is a manifest entry.
+type opfItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr"`
+}
+
+// hasProperty reports whether the item's space-separated properties
+// include p.
+func (it opfItem) hasProperty(p string) bool {
+	for _, f := range strings.Fields(it.Properties) {
+		if f == p {
+			return true
+		}
+	}
+	return false
+}
+
+// ncxNavPoint is an entry of the EPUB 2 NCX navigation map.
+type ncxNavPoint struct {
+	Label   string `xml:"navLabel>text"`
+	Content struct {
+		Src string `xml:"src,attr"`
+	} `xml:"content"`
+	Children []ncxNavPoint `xml:"navPoint"`
+}
+
+// epubLink is a table of contents entry before it is resolved to a
+// position: the zip path of the target document and the fragment.
+type epubLink struct {
+	label    string
+	file     string
+	fragment string
+}
+
+// epubChapter locates a spine document in the book text.
+type epubChapter struct {
+	index   int            // chapter index; may equal len(chapters) for trailing empty items
+	anchors map[string]int // element IDs to rune offsets within the chapter
+}
+
+// Open implements Reader. The book ID is the package's unique
+// identifier when present and the absolute path otherwise.
+func (EPUBReader) Open(filePath string) (LoadedBook, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	defer zr.Close()
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	if isEncryptedEPUB(files) {
+		return LoadedBook{}, ErrEncrypted
+	}
+
+	var container struct {
+		Rootfiles []struct {
+			FullPath string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err := decodeZipXML(files, "META-INF/container.xml", &container); err != nil {
+		return LoadedBook{}, fmt.Errorf("parse EPUB: %w", err)
+	}
+	if len(container.Rootfiles) == 0 {
+		return LoadedBook{}, fmt.Errorf("parse EPUB: container.xml names no package document")
+	}
+	opfPath := container.Rootfiles[0].FullPath
+	var pkg opfPackage
+	if err := decodeZipXML(files, opfPath, &pkg); err != nil {
+		return LoadedBook{}, fmt.Errorf("parse EPUB: %w", err)
+	}
+	items := make(map[string]opfItem, len(pkg.Manifest))
+	for _, it := range pkg.Manifest {
+		it.Href = resolveHref(opfPath, it.Href)
+		items[it.ID] = it
+	}
+
+	// Load the spine documents in reading order.
+	var (
+		text      strings.Builder
+		runes     int
+		chapters  []Chapter
+		headings  []string
+		locations = make(map[string]epubChapter)
+		notes     map[string]string
+		noteRefs  []FootnoteRef
+		spans     []Span
+	)
+	for _, ref := range pkg.Spine.ItemRefs {
+		it, ok := items[ref.IDRef]
+		if !ok || ref.Linear == "no" {
+			continue
+		}
+		if _, dup := locations[it.Href]; dup {
+			continue
+		}
+		doc, err := parseZipHTML(files, it.Href)
+		if err != nil {
+			return LoadedBook{}, fmt.Errorf("parse EPUB: %w", err)
+		}
+		w := htmlTextWriter{anchors: make(map[string]int)}
+		w.walk(doc)
+		chapterText := w.String()
+		locations[it.Href] = epubChapter{index: len(chapters), anchors: w.anchors}
+		for id, note := range w.notes {
+			if notes == nil {
+				notes = make(map[string]string)
+			}
+			notes[id] = note
+		}
+		if chapterText == "" {
+			continue
+		}
+		for _, ref := range w.noteRefs {
+			ref.Offset += runes
+			noteRefs = append(noteRefs, ref)
+		}
+		n := utf8.RuneCountInString(chapterText)
+		for _, span := range w.spans {
+			// String trims trailing whitespace a <pre> may end in.
+			if span.Length = min(span.Length, n-span.Offset); span.Length > 0 {
+				span.Offset += runes
+				spans = append(spans, span)
+			}
+		}
+		chapters = append(chapters, Chapter{Index: len(chapters), Offset: runes, Length: n})
+		headings = append(headings, w.heading())
+		text.WriteString(chapterText)
+		runes += n
+	}
+
+	id := uniqueIdentifier(pkg)
+	if id == "" {
+		id = filePath
+		if abs, err := filepath.Abs(filePath); err == nil {
+			id = abs
+		}
+	}
+	title := ""
+	if len(pkg.Metadata.Titles) > 0 {
+		title = strings.Join(strings.Fields(pkg.Metadata.Titles[0]), " ")
+	}
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+	var authors []string
+	for _, c := range pkg.Metadata.Creators {
+		if c = strings.Join(strings.Fields(c), " "); c != "" {
+			authors = append(authors, c)
+		}
+	}
+	book := Book{
+		ID:              BookID(id),
+		Title:           title,
+		Author:          strings.Join(authors, ", "),
+		Chapters:        chapters,
+		TotalCharacters: runes,
+		Metadata:        epubMetadata(pkg),
+	}
+
+	// Resolve the table of contents against the loaded chapters and use
+	// it to title them, falling back to each document's first heading.
+	var toc []TOCEntry
+	for _, l := range epubTOC(files, pkg, items) {
+		loc, ok := locations[l.file]
+		if !ok || len(chapters) == 0 {
+			continue
+		}
+		pos := Position{ChapterIndex: min(loc.index, len(chapters)-1)}
+		if off, ok := loc.anchors[l.fragment]; ok && loc.index < len(chapters) {
+			pos.OffsetInChapter = min(off, chapters[loc.index].Length-1)
+		}
+		if pos.OffsetInChapter == 0 && book.Chapters[pos.ChapterIndex].Title == "" {
+			book.Chapters[pos.ChapterIndex].Title = l.label
+		}
+		toc = append(toc, TOCEntry{Label: l.label, BookID: book.ID, Pos: pos})
+	}
+	for i := range book.Chapters {
+		if book.Chapters[i].Title == "" {
+			book.Chapters[i].Title = headings[i]
+		}
+	}
+
+	loaded := LoadedBook{Book: book, Text: text.String(), TOC: toc, Footnotes: notes, FootnoteRefs: noteRefs, Spans: spans}
+	if cover, ok := coverItem(pkg, items); ok {
+		if data, err := readZipFile(files, cover.Href); err == nil {
+			loaded.CoverImageData = data
+			loaded.CoverImageMIME = cover.MediaType
+		}
+	}
+	return loaded, nil
+}
+
+// uniqueIdentifier returns the dc:identifier the package names as its
+// unique identifier, or the first one.
+func uniqueIdentifier(pkg opfPackage) string {
+	ids := pkg.Metadata.Identifiers
+	for _, id := range ids {
+		if id.ID != "" && id.ID == pkg.UniqueID {
+			return strings.TrimSpace(id.Value)
+		}
+	}
+	if len(ids) > 0 {
+		return strings.TrimSpace(ids[0].Value)
+	}
+	return ""
+}
+
+// fontObfuscation lists the encryption.xml algorithms that only
+// obfuscate embedded fonts and leave the text readable.
+var fontObfuscation = map[string]bool{
+	"http://www.idpf.org/2008/embedding": true,
+	"http://ns.adobe.com/pdf/enc#RC":     true,
+}
+
+// isEncryptedEPUB reports whether the text of an EPUB cannot be read
+// without decrypting it: its ZIP entries are password-protected, which
+// archive/zip cannot read, or META-INF/encryption.xml lists a resource
+// encrypted with something other than font obfuscation, which means
+// DRM such as Adobe ADEPT or Readium LCP. Such books are reported as
+// ErrEncrypted rather than shown as garbled text.
+func isEncryptedEPUB(files map[string]*zip.File) bool {
+	for _, f := range files {
+		if f.Flags&0x1 != 0 { // ZIP "encrypted" flag
+			return true
+		}
+	}
+	if _, ok := files["META-INF/encryption.xml"]; !ok {
+		return false
+	}
+	var encryption struct {
+		Data []struct {
+			Method struct {
+				Algorithm string `xml:"Algorithm,attr"`
+			} `xml:"EncryptionMethod"`
+		} `xml:"EncryptedData"`
+	}
+	if err := decodeZipXML(files, "META-INF/encryption.xml", &encryption); err != nil {
+		return false
+	}
+	for _, d := range encryption.Data {
+		if !fontObfuscation[d.Method.Algorithm] {
+			return true
+		}
+	}
+	return false
+}
+
+// dcNamespace is the Dublin Core namespace of the OPF metadata
+// elements.
+const dcNamespace = "http://purl.org/dc/elements/1.1/"
+
+// epubMetadata returns the package's Dublin Core metadata other than
+// the title and creators, which Book has fields for, keyed by element
+// name. Repeated elements are joined with "; ", and the description,
+// which is often HTML, is reduced to its text.
+func epubMetadata(pkg opfPackage) map[string]string {
+	meta := make(map[string]string)
+	for _, e := range pkg.Metadata.Others {
+		if e.XMLName.Space != dcNamespace {
+			continue
+		}
+		value := e.Value
+		if e.XMLName.Local == "description" {
+			if doc, err := parseHTML(value); err == nil {
+				value = textContent(doc)
+			}
+		}
+		if value = strings.Join(strings.Fields(value), " "); value == "" {
+			continue
+		}
+		if prev, ok := meta[e.XMLName.Local]; ok {
+			value = prev + "; " + value
+		}
+		meta[e.XMLName.Local] = value
+	}
+	if id := uniqueIdentifier(pkg); id != "" {
+		meta["identifier"] = id
+	}
+	if len(meta) == 0 {
+		return nil
+	}
+	return meta
+}
+
+// coverItem finds the cover image: the EPUB 3 item with the
+// cover-image property, or the item named by the EPUB 2
+// <meta name="cover"> element.
+func coverItem(pkg opfPackage, items map[string]opfItem) (opfItem, bool) {
+	for _, it := range pkg.Manifest {
+		if it.hasProperty("cover-image") {
+			return items[it.ID], true
+		}
+	}
+	for _, m := range pkg.Metadata.Metas {
+		if m.Name == "cover" {
+			it, ok := items[m.Content]
+			return it, ok && strings.HasPrefix(it.MediaType, "image/")
+		}
+	}
+	return opfItem{}, false
+}
+
+// epubTOC returns the table of contents links in document order, from
+// the EPUB 3 navigation document if there is one and from the NCX
+// otherwise.
+func epubTOC(files map[string]*zip.File, pkg opfPackage, items map[string]opfItem) []epubLink {
+	for _, it := range pkg.Manifest {
+		if !it.hasProperty("nav") {
+			continue
+		}
+		navPath := items[it.ID].Href
+		doc, err := parseZipHTML(files, navPath)
+		if err != nil {
+			break
+		}
+		if links := navLinks(doc, navPath); len(links) > 0 {
+			return links
+		}
+		break
+	}
+
+	ncx, ok := items[pkg.Spine.TOC]
+	if !ok {
+		for _, it := range items {
+			if it.MediaType == "application/x-dtbncx+xml" {
+				ncx, ok = it, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil
+	}
+	var doc struct {
+		NavPoints []ncxNavPoint `xml:"navMap>navPoint"`
+	}
+	if err := decodeZipXML(files, ncx.Href, &doc); err != nil {
+		return nil
+	}
+	var links []epubLink
+	var visit func(points []ncxNavPoint)
+	visit = func(points []ncxNavPoint) {
+		for _, p := range points {
+			if label := strings.Join(strings.Fields(p.Label), " "); label != "" && p.Content.Src != "" {
+				links = append(links, newEPUBLink(label, ncx.Href, p.Content.Src))
+			}
+			visit(p.Children)
+		}
+	}
+	visit(doc.NavPoints)
+	return links
+}
+
+// navLinks collects the links of the toc <nav> of an EPUB 3 navigation
+// document at navPath, or of its first <nav> if none is marked as toc.
+func navLinks(doc *html.Node, navPath string) []epubLink {
+	var navs []*html.Node
+	var find func(n *html.Node)
+	find = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "nav" {
+			navs = append(navs, n)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			find(c)
+		}
+	}
+	find(doc)
+	if len(navs) == 0 {
+		return nil
+	}
+	nav := navs[0]
+	for _, n := range navs {
+		if strings.Contains(htmlAttr(n, "epub:type"), "toc") || htmlAttr(n, "role") == "doc-toc" {
+			nav = n
+			break
+		}
+	}
+
+	var links []epubLink
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			label := strings.Join(strings.Fields(textContent(n)), " ")
+			if href := htmlAttr(n, "href"); href != "" && label != "" {
+				links = append(links, newEPUBLink(label, navPath, href))
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(nav)
+	return links
+}
+
+// newEPUBLink resolves href, found in the document at base, to a link.
+func newEPUBLink(label, base, href string) epubLink {
+	file, fragment, _ := strings.Cut(href, "#")
+	return epubLink{label: label, file: resolveHref(base, file), fragment: fragment}
+}
+
+// resolveHref resolves an href found in the document at base to a path
+// within the zip archive.
+func resolveHref(base, href string) string {
+	if u, err := url.PathUnescape(href); err == nil {
+		href = u
+	}
+	return path.Join(path.Dir(base), href)
+}
+
+// readZipFile returns the contents of the archive member name.
+func readZipFile(files map[string]*zip.File, name string) ([]byte, error) {
+	f, ok := files[name]
+	if !ok {
+		return nil, fmt.Errorf("%s is missing", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// decodeZipXML unmarshals the XML archive member name into v.
+func decodeZipXML(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%s is missing", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := newXMLDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// parseZipHTML parses the (X)HTML archive member name.
+func parseZipHTML(files map[string]*zip.File, name string) (*html.Node, error) {
+	data, err := readZipFile(files, name)
+	if err != nil {
+		return nil, err
+	}
+	return parseHTML(string(data))
+}
+
+// parseHTML parses (X)HTML markup the way a reader without scripting
+// would: with scripting enabled, the content of <noscript> would be
+// left as raw text, tags and all.
+func parseHTML(markup string) (*html.Node, error) {
+	// The HTML parser assumes UTF-8; keep the text valid regardless.
+	return html.ParseWithOptions(strings.NewReader(strings.ToValidUTF8(markup, "\uFFFD")), html.ParseOptionEnableScripting(false))
+}
+
+// htmlAttr returns the value of the attribute key of n, or "".
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// hasSemantic reports whether n is marked as kind (e.g. "noteref") by
+// its EPUB 3 epub:type or its ARIA role (e.g. "doc-noteref").
+func hasSemantic(n *html.Node, kind string) bool {
+	for _, t := range strings.Fields(htmlAttr(n, "epub:type")) {
+		if t == kind {
+			return true
+		}
+	}
+	return htmlAttr(n, "role") == "doc-"+kind
+}
+
+// textContent returns the concatenated text below n.
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// htmlHeading is a heading element found by htmlTextWriter.
+type htmlHeading struct {
+	offset int // rune offset of the heading text
+	level  int // 1 for h1, and so on
+	text   string
+}
+
+// htmlBlocks are the elements that start a new paragraph.
+var htmlBlocks = map[string]bool{
+	"p": true, "div": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "li": true, "ul": true, "ol": true, "dl": true,
+	"dt": true, "dd": true, "blockquote": true, "pre": true, "hr": true,
+	"table": true, "tr": true, "section": true, "article": true, "aside": true,
+	"header": true, "footer": true, "figure": true, "figcaption": true,
+}
+
+// htmlTextWriter converts an HTML document to plain text: paragraphs
+// separated by blank lines, whitespace collapsed except in <pre>, and
+// markup and entities removed. It records the offset of every element
+// ID so that links with fragments can be resolved.
+type htmlTextWriter struct {
+	b       strings.Builder
+	runes   int
+	anchors map[string]int
+	// noteRefs are the EPUB 3 footnote links (epub:type="noteref"), with
+	// offsets within the document, and notes the text of the footnote
+	// asides by ID. The asides are left out of the text.
+	noteRefs []FootnoteRef
+	notes    map[string]string
+	// headings lists the h1–h6 elements and pageBreaks the offsets of
+	// MobiPocket <mbp:pagebreak> elements, for loaders that split the
+	// text into chapters themselves.
+	headings   []htmlHeading
+	pageBreaks []int
+	// spans are the runs of text inside <code> and <pre> elements.
+	spans []Span
+
+	breaks       int  // newlines owed before the next text
+	pendingSpace bool // a space is owed before the next text
+	atLineStart  bool
+	pre          int // depth of <pre> elements
+	code         int // depth of <code> and <pre> elements
+	codeStart    int // offset of the current code span's text, or -1
+}
+
+// walk writes n and its descendants.
+func (w *htmlTextWriter) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data)
+		return
+	case html.ElementNode:
+		switch n.Data {
+		case "head", "script", "style":
+			return
+		case "br":
+			w.breaks = min(w.breaks+1, 2)
+			return
+		case "mbp:pagebreak":
+			w.pageBreaks = append(w.pageBreaks, w.offset())
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			// Owe the block's breaks before taking its offset.
+			w.breaks = 2
+			w.headings = append(w.headings, htmlHeading{
+				offset: w.offset(),
+				level:  int(n.Data[1] - '0'),
+				text:   strings.Join(strings.Fields(textContent(n)), " "),
+			})
+		case "pre":
+			w.pre++
+			defer func() { w.pre-- }()
+			defer w.enterCode()()
+		case "code":
+			defer w.enterCode()()
+		case "a":
+			if _, id, ok := strings.Cut(htmlAttr(n, "href"), "#"); ok && id != "" && hasSemantic(n, "noteref") {
+				defer func() {
+					marker := strings.Join(strings.Fields(textContent(n)), " ")
+					if length := utf8.RuneCountInString(marker); length > 0 {
+						w.noteRefs = append(w.noteRefs, FootnoteRef{Offset: w.runes - length, Length: length, ID: id})
+					}
+				}()
+			}
+		case "aside":
+			if id := htmlAttr(n, "id"); id != "" && (hasSemantic(n, "footnote") || hasSemantic(n, "endnote") || hasSemantic(n, "rearnote")) {
+				if w.notes == nil {
+					w.notes = make(map[string]string)
+				}
+				w.notes[id] = strings.Join(strings.Fields(textContent(n)), " ")
+				return
+			}
+		}
+		if htmlBlocks[n.Data] {
+			w.breaks = 2
+			defer func() { w.breaks = 2 }()
+		}
+		if id := htmlAttr(n, "id"); id != "" {
+			if _, seen := w.anchors[id]; !seen {
+				w.anchors[id] = w.offset()
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c)
+	}
+}
+
+// offset returns the rune offset at which the next text will start,
+// after any owed breaks.
+func (w *htmlTextWriter) offset() int {
+	if w.runes == 0 {
+		return 0
+	}
+	return w.runes + w.breaks
+}
+
+// heading returns the text of the first heading, or "".
+func (w *htmlTextWriter) heading() string {
+	if len(w.headings) == 0 {
+		return ""
+	}
+	return w.headings[0].text
+}
+
+// text writes the content of a text node.
+func (w *htmlTextWriter) text(s string) {
+	if w.pre > 0 {
+		w.flushBreaks()
+		w.startCode()
+		w.write(s)
+		return
+	}
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		w.pendingSpace = w.pendingSpace || s != ""
+		return
+	}
+	if r, _ := utf8.DecodeRuneInString(s); unicode.IsSpace(r) {
+		w.pendingSpace = true
+	}
+	w.flushBreaks()
+	if w.pendingSpace && !w.atLineStart && w.runes > 0 {
+		w.write(" ")
+	}
+	w.startCode()
+	w.write(strings.Join(fields, " "))
+	r, _ := utf8.DecodeLastRuneInString(s)
+	w.pendingSpace = unicode.IsSpace(r)
+}
+
+// enterCode starts a code element and returns the function that ends
+// it. The outermost code element's text becomes a span.
+func (w *htmlTextWriter) enterCode() func() {
+	if w.code == 0 {
+		w.codeStart = -1
+	}
+	w.code++
+	return func() {
+		w.code--
+		if w.code == 0 && w.codeStart >= 0 && w.runes > w.codeStart {
+			w.spans = append(w.spans, Span{Offset: w.codeStart, Length: w.runes - w.codeStart, CodeBlock: true})
+		}
+	}
+}
+
+// startCode notes where the text of a code element starts, so that its
+// span leaves out the breaks and space written before it.
+func (w *htmlTextWriter) startCode() {
+	if w.code > 0 && w.codeStart < 0 {
+		w.codeStart = w.runes
+	}
+}
+
+// flushBreaks writes the newlines owed before the next text, if any
+// text precedes it.
+func (w *htmlTextWriter) flushBreaks() {
+	if w.breaks > 0 && w.runes > 0 {
+		w.write(strings.Repeat("\n", w.breaks))
+		w.atLineStart = true
+		w.pendingSpace = false
+	}
+	w.breaks = 0
+}
+
+// write appends s to the text.
+func (w *htmlTextWriter) write(s string) {
+	w.b.WriteString(s)
+	w.runes += utf8.RuneCountInString(s)
+	w.atLineStart = strings.HasSuffix(s, "\n")
+}
+
+// String returns the text, ending in a blank line unless it is empty,
+// so that concatenated documents stay separate paragraphs.
+func (w *htmlTextWriter) String() string {
+	if w.runes == 0 {
+		return ""
+	}
+	return strings.TrimRight(w.b.String(), " \n") + "\n\n"
+}
@@ -0,0 +1,161 @@
+package reader
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// writeEPUB writes an EPUB holding files, plus the mimetype and a
+// container.xml naming content.opf, and returns its path.
+func writeEPUB(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "book.epub")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	write := func(name, content string) {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("mimetype", "application/epub+zip")
+	write("META-INF/container.xml", `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`)
+	for name, content := range files {
+		write(name, content)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// epubPackage returns a package document whose manifest and spine list
+// the given chapter files; extraItems are added to the manifest.
+func epubPackage(chapters []string, extraItems string) string {
+	var manifest, spine strings.Builder
+	for i, name := range chapters {
+		id := "c" + strconv.Itoa(i+1)
+		manifest.WriteString(`<item id="` + id + `" href="` + name + `" media-type="application/xhtml+xml"/>`)
+		spine.WriteString(`<itemref idref="` + id + `"/>`)
+	}
+	return `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="id">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="id">test-book</dc:identifier>
+    <dc:title>Test Book</dc:title>
+  </metadata>
+  <manifest>` + manifest.String() + extraItems + `</manifest>
+  <spine>` + spine.String() + `</spine>
+</package>`
+}
+
+// xhtml wraps body in an XHTML document.
+func xhtml(body string) string {
+	return `<?xml version="1.0" encoding="utf-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><head><title>x</title></head><body>` + body + `</body></html>`
+}
+
+func TestEPUBKeepsEscapedMarkup(t *testing.T) {
+	path := writeEPUB(t, map[string]string{
+		"content.opf": epubPackage([]string{"ch1.xhtml"}, ""),
+		"ch1.xhtml": xhtml(`<p>Use <code>std::vector&lt;int&gt;</code> or <code>List&lt;String&gt;</code>.</p>
+<pre>#include &lt;iostream&gt;</pre>
+<noscript><p>No scripts here.</p></noscript>`),
+	})
+	book, err := NewEPUBReader().Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"std::vector<int>", "List<String>", "#include <iostream>", "No scripts here."} {
+		if !strings.Contains(book.Text, want) {
+			t.Errorf("text %q does not contain %q", book.Text, want)
+		}
+	}
+	if strings.Contains(book.Text, "<p>") {
+		t.Errorf("text %q contains markup", book.Text)
+	}
+}
+
+func TestEPUBNavTOC(t *testing.T) {
+	navItem := `<item id="nav" href="nav/toc.xhtml" media-type="application/xhtml+xml" properties="nav"/>`
+	path := writeEPUB(t, map[string]string{
+		"content.opf": epubPackage([]string{"ch1.xhtml", "ch2.xhtml"}, navItem),
+		"ch1.xhtml":   xhtml(`<h1>One</h1><p>Opening words.</p><h2 id="s11">A section</h2><p>More words.</p>`),
+		"ch2.xhtml":   xhtml(`<h1>Two</h1><p>The end.</p>`),
+		// The page list comes first, but the toc nav is the one used.
+		"nav/toc.xhtml": xhtml(`<nav epub:type="page-list"><ol><li><a href="../ch2.xhtml">Page 2</a></li></ol></nav>
+<nav epub:type="toc"><ol>
+  <li><a href="../ch1.xhtml">Chapter  One</a>
+    <ol><li><a href="../ch1.xhtml#s11">Section 1.1</a></li></ol></li>
+  <li><a href="../ch2.xhtml">Chapter Two</a></li>
+</ol></nav>`),
+	})
+	book, err := NewDefaultUnifiedReader().Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range ValidateBook(book) {
+		t.Errorf("ValidateBook: %s", w)
+	}
+
+	section := strings.Index(book.Text, "A section")
+	want := []TOCEntry{
+		{Label: "Chapter One", BookID: "test-book", Pos: Position{ChapterIndex: 0}},
+		{Label: "Section 1.1", BookID: "test-book", Pos: Position{ChapterIndex: 0, OffsetInChapter: len([]rune(book.Text[:section]))}},
+		{Label: "Chapter Two", BookID: "test-book", Pos: Position{ChapterIndex: 1}},
+	}
+	if len(book.TOC) != len(want) {
+		t.Fatalf("TOC = %+v, want %+v", book.TOC, want)
+	}
+	for i := range want {
+		if book.TOC[i] != want[i] {
+			t.Errorf("TOC[%d] = %+v, want %+v", i, book.TOC[i], want[i])
+		}
+	}
+	if got := book.Book.Chapters[1].Title; got != "Chapter Two" {
+		t.Errorf("chapter 2 title = %q, want the TOC label", got)
+	}
+}
+
+func TestEPUBChapterTitlesTrimmed(t *testing.T) {
+	navItem := `<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>`
+	path := writeEPUB(t, map[string]string{
+		"content.opf": epubPackage([]string{"ch1.xhtml", "ch2.xhtml", "ch3.xhtml"}, navItem),
+		"ch1.xhtml":   xhtml("<p>First.</p>"),
+		"ch2.xhtml":   xhtml("<h1>\n\t  Heading &#160;Two \n</h1><p>Second.</p>"),
+		"ch3.xhtml":   xhtml("<h1> \n </h1><p>Third.</p>"),
+		"nav.xhtml": xhtml(`<nav epub:type="toc"><ol>
+  <li><a href="ch1.xhtml">
+      Chapter One
+  </a></li>
+  <li><a href="ch3.xhtml">   </a></li>
+</ol></nav>`),
+	})
+	book, err := NewDefaultUnifiedReader().Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Chapter One", "Heading Two", ""}
+	if len(book.Book.Chapters) != len(want) {
+		t.Fatalf("got %d chapters, want %d", len(book.Book.Chapters), len(want))
+	}
+	for i, ch := range book.Book.Chapters {
+		if ch.Title != strings.TrimSpace(ch.Title) || ch.Title != want[i] {
+			t.Errorf("chapter %d title = %q, want %q", i, ch.Title, want[i])
+		}
+	}
+}
@@ -0,0 +1,455 @@
+package reader
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// FB2Reader loads FictionBook 2 files. Every <section> of the main
+// body becomes a chapter titled by its <title>; paragraphs are
+// separated by blank lines and poem verses by line breaks. Bodies named
+// "notes" or "comments" hold footnotes: they are left out of the text
+// and become the book's Footnotes, referred to by <a type="note">
+// links.
+type FB2Reader struct{}
+
+// NewFB2Reader returns a reader for .fb2 files.
+func NewFB2Reader() FB2Reader {
+	return FB2Reader{}
+}
+
+// Extensions implements Reader.
+func (FB2Reader) Extensions() []string {
+	return []string{".fb2"}
+}
+
+// Open implements Reader. The book ID is the document-info <id> when
+// present and the absolute path otherwise.
+func (FB2Reader) Open(path string) (LoadedBook, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	defer f.Close()
+
+	p := fb2Parser{}
+	if err := p.parse(f); err != nil {
+		return LoadedBook{}, fmt.Errorf("parse FB2: %w", err)
+	}
+
+	id := p.id
+	if id == "" {
+		id = path
+		if abs, err := filepath.Abs(path); err == nil {
+			id = abs
+		}
+	}
+	title := p.title
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	text := p.text.String()
+	book := Book{
+		ID:              BookID(id),
+		Title:           title,
+		Author:          strings.Join(p.authors, ", "),
+		Chapters:        p.finishChapters(),
+		TotalCharacters: utf8.RuneCountInString(text),
+	}
+	return LoadedBook{
+		Book:           book,
+		Text:           text,
+		TOC:            chapterTOC(book),
+		CoverImageData: p.coverData,
+		CoverImageMIME: p.coverMIME,
+		Footnotes:      p.notes,
+		FootnoteRefs:   p.noteRefs,
+	}, nil
+}
+
+// fb2Author is the <author> element of <title-info>.
+type fb2Author struct {
+	First    string `xml:"first-name"`
+	Middle   string `xml:"middle-name"`
+	Last     string `xml:"last-name"`
+	Nickname string `xml:"nickname"`
+}
+
+// name returns the author's display name.
+func (a fb2Author) name() string {
+	name := strings.Join(strings.Fields(a.First+" "+a.Middle+" "+a.Last), " ")
+	if name == "" {
+		name = strings.TrimSpace(a.Nickname)
+	}
+	return name
+}
+
+// fb2Parser accumulates the state of a single FB2 parse.
+type fb2Parser struct {
+	id      string
+	title   string
+	authors []string
+
+	text     strings.Builder
+	runes    int // rune length of text
+	chapters []Chapter
+
+	// inBody is true inside a <body> that is being read.
+	inBody bool
+	// para collects the text of the block element being read; inPara
+	// is true while inside one.
+	para   strings.Builder
+	inPara bool
+	// titleParts collects the paragraphs of the current section <title>
+	// while inTitle is true.
+	titleParts []string
+	inTitle    bool
+
+	coverID   string
+	coverData []byte
+	coverMIME string
+
+	// paraNotes are the targets of the note links in para, whose text
+	// is marked with noteStart and noteEnd until the paragraph is
+	// written; inNote is true inside one. notes and noteRefs collect the
+	// footnotes.
+	paraNotes []string
+	inNote    bool
+	notes     map[string]string
+	noteRefs  []FootnoteRef
+}
+
+// noteStart and noteEnd mark a note link's text within a paragraph.
+// They are private-use characters, which do not occur in book text.
+const (
+	noteStart = '\uE000'
+	noteEnd   = '\uE001'
+)
+
+// fb2Text is the text of an element with its inline markup removed.
+type fb2Text string
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (t *fb2Text) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var b strings.Builder
+	for depth := 0; ; {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				*t = fb2Text(strings.Join(strings.Fields(b.String()), " "))
+				return nil
+			}
+			depth--
+		case xml.CharData:
+			b.Write(tok)
+		}
+	}
+}
+
+// fb2NoteSection is a footnote: a <section> of a notes body.
+type fb2NoteSection struct {
+	ID         string    `xml:"id,attr"`
+	Paragraphs []fb2Text `xml:"p"`
+}
+
+// parse reads the whole document from r.
+func (p *fb2Parser) parse(r io.Reader) error {
+	d := newXMLDecoder(r)
+	var stack []string
+	parent := func() string {
+		if len(stack) == 0 {
+			return ""
+		}
+		return stack[len(stack)-1]
+	}
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			handled, err := p.start(d, t, parent())
+			if err != nil {
+				return err
+			}
+			if !handled {
+				stack = append(stack, t.Name.Local)
+			}
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			p.end(t.Name.Local)
+		case xml.CharData:
+			if p.inPara {
+				p.para.Write(t)
+			}
+		}
+	}
+}
+
+// newXMLDecoder returns a decoder for book XML: it accepts the HTML
+// named entities (such as &nbsp;) common in FB2 and EPUB files, and
+// documents in any encoding the HTML standard knows (e.g.
+// windows-1251).
+func newXMLDecoder(r io.Reader) *xml.Decoder {
+	d := xml.NewDecoder(r)
+	d.Entity = xml.HTMLEntity
+	d.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		enc, err := htmlindex.Get(label)
+		if err != nil {
+			return nil, err
+		}
+		return enc.NewDecoder().Reader(input), nil
+	}
+	return d
+}
+
+// start handles an opening tag whose parent element is parent. It
+// reports whether it consumed the whole element, in which case no
+// matching end tag will follow.
+func (p *fb2Parser) start(d *xml.Decoder, t xml.StartElement, parent string) (bool, error) {
+	switch t.Name.Local {
+	case "book-title":
+		if parent == "title-info" {
+			var s string
+			err := d.DecodeElement(&s, &t)
+			p.title = strings.Join(strings.Fields(s), " ")
+			return true, err
+		}
+	case "author":
+		if parent == "title-info" {
+			var a fb2Author
+			err := d.DecodeElement(&a, &t)
+			if name := a.name(); name != "" {
+				p.authors = append(p.authors, name)
+			}
+			return true, err
+		}
+	case "id":
+		if parent == "document-info" {
+			var s string
+			err := d.DecodeElement(&s, &t)
+			p.id = strings.TrimSpace(s)
+			return true, err
+		}
+	case "coverpage":
+		var cover struct {
+			Images []struct {
+				Href string `xml:"href,attr"`
+			} `xml:"image"`
+		}
+		err := d.DecodeElement(&cover, &t)
+		if len(cover.Images) > 0 {
+			p.coverID = strings.TrimPrefix(cover.Images[0].Href, "#")
+		}
+		return true, err
+	case "binary":
+		if p.coverID == "" || attr(t, "id") != p.coverID {
+			return true, d.Skip()
+		}
+		var s string
+		if err := d.DecodeElement(&s, &t); err != nil {
+			return true, err
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+		if err == nil {
+			p.coverData = data
+			p.coverMIME = attr(t, "content-type")
+		}
+		return true, nil
+	case "body":
+		if name := attr(t, "name"); name == "notes" || name == "comments" {
+			var notes struct {
+				Sections []fb2NoteSection `xml:"section"`
+			}
+			err := d.DecodeElement(&notes, &t)
+			for _, s := range notes.Sections {
+				if s.ID == "" {
+					continue
+				}
+				paragraphs := make([]string, 0, len(s.Paragraphs))
+				for _, para := range s.Paragraphs {
+					paragraphs = append(paragraphs, string(para))
+				}
+				if p.notes == nil {
+					p.notes = make(map[string]string)
+				}
+				p.notes[s.ID] = strings.Join(paragraphs, "\n")
+			}
+			return true, err
+		}
+		p.inBody = true
+	}
+	if !p.inBody {
+		// E.g. the annotation in <description> also has paragraphs.
+		return false, nil
+	}
+
+	switch t.Name.Local {
+	case "section":
+		p.startChapter()
+	case "title":
+		p.inTitle = true
+		p.titleParts = nil
+	case "p", "v", "subtitle", "text-author":
+		p.inPara = true
+		p.para.Reset()
+		p.paraNotes = p.paraNotes[:0]
+	case "empty-line":
+		p.write("\n")
+	case "a":
+		if href := attr(t, "href"); p.inPara && attr(t, "type") == "note" && strings.HasPrefix(href, "#") {
+			p.paraNotes = append(p.paraNotes, href[1:])
+			p.inNote = true
+			p.para.WriteRune(noteStart)
+		}
+	}
+	return false, nil
+}
+
+// end handles a closing tag.
+func (p *fb2Parser) end(name string) {
+	if name == "body" {
+		p.inBody = false
+	}
+	if !p.inBody {
+		return
+	}
+	switch name {
+	case "a":
+		if p.inNote {
+			p.inNote = false
+			p.para.WriteRune(noteEnd)
+		}
+	case "p", "v", "subtitle", "text-author":
+		if !p.inPara {
+			return
+		}
+		p.inPara = false
+		line := p.takeNoteRefs(strings.Join(strings.Fields(p.para.String()), " "), !p.inTitle)
+		if p.inTitle {
+			if line != "" {
+				p.titleParts = append(p.titleParts, line)
+			}
+			return
+		}
+		if line == "" {
+			return
+		}
+		if name == "v" {
+			p.write(line + "\n")
+		} else {
+			p.write(line + "\n\n")
+		}
+	case "stanza":
+		p.write("\n")
+	case "title":
+		p.inTitle = false
+		if len(p.titleParts) == 0 {
+			return
+		}
+		if len(p.chapters) > 0 && p.chapters[len(p.chapters)-1].Title == "" {
+			p.chapters[len(p.chapters)-1].Title = strings.Join(p.titleParts, " ")
+		}
+		p.write(strings.Join(p.titleParts, "\n") + "\n\n")
+	}
+}
+
+// takeNoteRefs removes the note link marks from a paragraph about to be
+// written at the end of the text, recording the links as footnote
+// references if record is set.
+func (p *fb2Parser) takeNoteRefs(line string, record bool) string {
+	if !strings.ContainsRune(line, noteStart) {
+		return line
+	}
+	var b strings.Builder
+	runes, start, note := 0, 0, 0
+	for _, r := range line {
+		switch r {
+		case noteStart:
+			start = runes
+		case noteEnd:
+			if record && runes > start && note < len(p.paraNotes) {
+				p.noteRefs = append(p.noteRefs, FootnoteRef{Offset: p.runes + start, Length: runes - start, ID: p.paraNotes[note]})
+			}
+			note++
+		default:
+			b.WriteRune(r)
+			runes++
+		}
+	}
+	return b.String()
+}
+
+// startChapter begins a chapter at the current end of the text. A
+// previous chapter that is still empty (e.g. a section that only wraps
+// subsections) is reused rather than left with zero length.
+func (p *fb2Parser) startChapter() {
+	if n := len(p.chapters); n > 0 && p.chapters[n-1].Offset == p.runes {
+		p.chapters[n-1].Title = ""
+		return
+	}
+	p.chapters = append(p.chapters, Chapter{Index: len(p.chapters), Offset: p.runes})
+}
+
+// write appends s to the book text.
+func (p *fb2Parser) write(s string) {
+	if len(p.chapters) == 0 {
+		// Text before the first section, such as the body title.
+		p.chapters = append(p.chapters, Chapter{Offset: 0})
+	}
+	p.text.WriteString(s)
+	p.runes += utf8.RuneCountInString(s)
+}
+
+// finishChapters returns the chapters with exact lengths, so that they
+// tile the text. A trailing empty chapter is dropped.
+func (p *fb2Parser) finishChapters() []Chapter {
+	chapters := p.chapters
+	if n := len(chapters); n > 1 && chapters[n-1].Offset == p.runes {
+		chapters = chapters[:n-1]
+	}
+	if len(chapters) == 0 {
+		return []Chapter{{Index: 0, Length: p.runes}}
+	}
+	chapters[0].Offset = 0
+	for i := range chapters {
+		end := p.runes
+		if i+1 < len(chapters) {
+			end = chapters[i+1].Offset
+		}
+		chapters[i].Index = i
+		chapters[i].Length = end - chapters[i].Offset
+	}
+	return chapters
+}
+
+// attr returns the value of the attribute with the given local name,
+// ignoring its namespace, or "".
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
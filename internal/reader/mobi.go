@@ -0,0 +1,339 @@
+package reader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ErrEncrypted is returned when a book is DRM-protected.
+var ErrEncrypted = errors.New("book is DRM-protected")
+
+// MOBIReader loads unencrypted MobiPocket and Kindle (.mobi, .azw3)
+// books. The text records are decompressed and their HTML converted to
+// plain text; chapters start at <mbp:pagebreak> markers or, in books
+// without them, at the top-level headings.
+type MOBIReader struct{}
+
+// NewMOBIReader returns a reader for .mobi and .azw3 files.
+func NewMOBIReader() MOBIReader {
+	return MOBIReader{}
+}
+
+// Extensions implements Reader.
+func (MOBIReader) Extensions() []string {
+	return []string{".mobi", ".azw3"}
+}
+
+// MOBI compression types, from the PalmDOC header.
+const (
+	mobiNoCompression     = 1
+	mobiPalmDOCCompressed = 2
+	mobiHuffCDICompressed = 17480
+)
+
+// EXTH record types used by the reader.
+const (
+	exthAuthor      = 100
+	exthASIN        = 113
+	exthCoverOffset = 201
+	exthTitle       = 503
+)
+
+// Open implements Reader. The book ID is the ASIN when present and the
+// absolute path otherwise.
+func (MOBIReader) Open(path string) (LoadedBook, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	records, err := palmDBRecords(data)
+	if err != nil {
+		return LoadedBook{}, fmt.Errorf("parse MOBI: %w", err)
+	}
+	r0 := records[0]
+	if len(r0) < 16 {
+		return LoadedBook{}, fmt.Errorf("parse MOBI: record 0 is too short")
+	}
+	compression := binary.BigEndian.Uint16(r0[0:])
+	textRecords := int(binary.BigEndian.Uint16(r0[8:]))
+	if binary.BigEndian.Uint16(r0[12:]) != 0 {
+		return LoadedBook{}, ErrEncrypted
+	}
+
+	// The MOBI header follows the 16-byte PalmDOC header. Old PalmDOC
+	// files have none and use the defaults.
+	var (
+		encoding   uint32 = 1252
+		extraFlags uint16
+		firstImage = -1
+		fullName   string
+		exth       map[uint32][]byte
+	)
+	if len(r0) >= 0x84 && string(r0[16:20]) == "MOBI" {
+		headerLen := int(binary.BigEndian.Uint32(r0[20:]))
+		encoding = binary.BigEndian.Uint32(r0[28:])
+		nameOff := int(binary.BigEndian.Uint32(r0[0x54:]))
+		nameLen := int(binary.BigEndian.Uint32(r0[0x58:]))
+		if nameOff >= 0 && nameLen >= 0 && nameOff+nameLen <= len(r0) {
+			fullName = string(r0[nameOff : nameOff+nameLen])
+		}
+		firstImage = int(binary.BigEndian.Uint32(r0[0x6C:]))
+		if headerLen >= 0xE4 && len(r0) >= 0xF4 {
+			extraFlags = binary.BigEndian.Uint16(r0[0xF2:])
+		}
+		if binary.BigEndian.Uint32(r0[0x80:])&0x40 != 0 {
+			exth = parseEXTH(r0[min(16+headerLen, len(r0)):])
+		}
+	}
+
+	var raw bytes.Buffer
+	for i := 1; i <= textRecords && i < len(records); i++ {
+		rec := records[i]
+		rec = rec[:len(rec)-trailingEntriesSize(rec, extraFlags)]
+		switch compression {
+		case mobiNoCompression:
+			raw.Write(rec)
+		case mobiPalmDOCCompressed:
+			raw.Write(palmDOCDecompress(rec))
+		case mobiHuffCDICompressed:
+			return LoadedBook{}, fmt.Errorf("%w: HUFF/CDIC compressed MOBI", ErrUnsupportedFormat)
+		default:
+			return LoadedBook{}, fmt.Errorf("%w: MOBI compression %d", ErrUnsupportedFormat, compression)
+		}
+	}
+
+	markup := raw.String()
+	if encoding == 1252 {
+		if s, err := charmap.Windows1252.NewDecoder().String(markup); err == nil {
+			markup = s
+		}
+	}
+	fullName = decodeMOBIString(fullName, encoding)
+
+	doc, err := parseHTML(markup)
+	if err != nil {
+		return LoadedBook{}, fmt.Errorf("parse MOBI: %w", err)
+	}
+	w := htmlTextWriter{anchors: make(map[string]int)}
+	w.walk(doc)
+	text := w.String()
+
+	id := string(exth[exthASIN])
+	if id == "" {
+		id = path
+		if abs, err := filepath.Abs(path); err == nil {
+			id = abs
+		}
+	}
+	title := decodeMOBIString(string(exth[exthTitle]), encoding)
+	if title == "" {
+		title = fullName
+	}
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	book := Book{
+		ID:              BookID(id),
+		Title:           strings.Join(strings.Fields(title), " "),
+		Author:          strings.TrimSpace(decodeMOBIString(string(exth[exthAuthor]), encoding)),
+		Chapters:        mobiChapters(&w, utf8.RuneCountInString(text)),
+		TotalCharacters: utf8.RuneCountInString(text),
+	}
+	loaded := LoadedBook{Book: book, Text: text}
+
+	if off, ok := exth[exthCoverOffset]; ok && len(off) == 4 && firstImage > 0 {
+		if i := firstImage + int(binary.BigEndian.Uint32(off)); i < len(records) {
+			loaded.CoverImageData = records[i]
+			loaded.CoverImageMIME = http.DetectContentType(records[i])
+		}
+	}
+	return loaded, nil
+}
+
+// palmDBRecords splits a PalmDB file into its records.
+func palmDBRecords(data []byte) ([][]byte, error) {
+	if len(data) < 78 {
+		return nil, errors.New("file is too short for a PalmDB header")
+	}
+	n := int(binary.BigEndian.Uint16(data[76:]))
+	if n == 0 || len(data) < 78+8*n {
+		return nil, errors.New("invalid PalmDB record list")
+	}
+	offsets := make([]int, n+1)
+	for i := range n {
+		offsets[i] = int(binary.BigEndian.Uint32(data[78+8*i:]))
+	}
+	offsets[n] = len(data)
+	records := make([][]byte, n)
+	for i := range n {
+		start, end := offsets[i], offsets[i+1]
+		if start > end || end > len(data) {
+			return nil, fmt.Errorf("invalid offset of record %d", i)
+		}
+		records[i] = data[start:end]
+	}
+	return records, nil
+}
+
+// parseEXTH returns the records of the EXTH header at the start of b,
+// by type; for repeated types the first record wins.
+func parseEXTH(b []byte) map[uint32][]byte {
+	out := make(map[uint32][]byte)
+	if len(b) < 12 || string(b[:4]) != "EXTH" {
+		return out
+	}
+	count := int(binary.BigEndian.Uint32(b[8:]))
+	pos := 12
+	for range count {
+		if pos+8 > len(b) {
+			break
+		}
+		typ := binary.BigEndian.Uint32(b[pos:])
+		size := int(binary.BigEndian.Uint32(b[pos+4:]))
+		if size < 8 || pos+size > len(b) {
+			break
+		}
+		if _, seen := out[typ]; !seen {
+			out[typ] = b[pos+8 : pos+size]
+		}
+		pos += size
+	}
+	return out
+}
+
+// trailingEntriesSize returns the number of bytes at the end of a text
+// record that are not text, as announced by the extra data flags of the
+// MOBI header: one backward-encoded, size-prefixed entry per flag bit
+// above bit 0, then, with bit 0, the bytes of a multibyte character
+// continued in the next record.
+func trailingEntriesSize(rec []byte, flags uint16) int {
+	size := 0
+	for f := flags >> 1; f != 0; f >>= 1 {
+		if f&1 == 0 {
+			continue
+		}
+		// The entry size is a varint read backwards from the end, whose
+		// first byte has the high bit set.
+		end := len(rec) - size
+		n, shift := 0, 0
+		for i := end - 1; i >= 0 && shift < 28; i-- {
+			b := rec[i]
+			n |= int(b&0x7F) << shift
+			shift += 7
+			if b&0x80 != 0 {
+				break
+			}
+		}
+		size += n
+	}
+	if flags&1 != 0 && size < len(rec) {
+		size += int(rec[len(rec)-size-1]&0x3) + 1
+	}
+	return min(size, len(rec))
+}
+
+// palmDOCDecompress expands a record compressed with the PalmDOC LZ77
+// variant.
+func palmDOCDecompress(in []byte) []byte {
+	out := make([]byte, 0, 4096)
+	for i := 0; i < len(in); {
+		c := in[i]
+		i++
+		switch {
+		case c >= 1 && c <= 8:
+			// Literal run of c bytes.
+			end := min(i+int(c), len(in))
+			out = append(out, in[i:end]...)
+			i = end
+		case c < 0x80:
+			out = append(out, c)
+		case c >= 0xC0:
+			// A space followed by an ASCII character.
+			out = append(out, ' ', c^0x80)
+		default:
+			// Back reference: 11 bits of distance, 3 bits of length.
+			if i >= len(in) {
+				return out
+			}
+			pair := int(c)<<8 | int(in[i])
+			i++
+			distance := (pair >> 3) & 0x7FF
+			length := pair&7 + 3
+			if distance == 0 || distance > len(out) {
+				continue
+			}
+			for range length {
+				out = append(out, out[len(out)-distance])
+			}
+		}
+	}
+	return out
+}
+
+// decodeMOBIString converts a metadata string from the book's text
+// encoding (1252 for Windows-1252, 65001 for UTF-8) to UTF-8.
+func decodeMOBIString(s string, encoding uint32) string {
+	if encoding == 1252 {
+		if d, err := charmap.Windows1252.NewDecoder().String(s); err == nil {
+			return d
+		}
+	}
+	return strings.ToValidUTF8(s, "\uFFFD")
+}
+
+// mobiChapters splits text of total runes, as produced by w, into
+// chapters at its page breaks or, without those, at its highest-level
+// headings. Each chapter is titled by the first heading at its start.
+func mobiChapters(w *htmlTextWriter, total int) []Chapter {
+	starts := w.pageBreaks
+	if len(starts) == 0 && len(w.headings) > 0 {
+		top := 6
+		for _, h := range w.headings {
+			top = min(top, h.level)
+		}
+		for _, h := range w.headings {
+			if h.level == top {
+				starts = append(starts, h.offset)
+			}
+		}
+	}
+	starts = append([]int{0}, starts...)
+	sort.Ints(starts)
+
+	var chapters []Chapter
+	for _, off := range starts {
+		if off >= total && len(chapters) > 0 {
+			break
+		}
+		if n := len(chapters); n > 0 && chapters[n-1].Offset == off {
+			continue
+		}
+		chapters = append(chapters, Chapter{Index: len(chapters), Offset: off})
+	}
+	h := 0
+	for i := range chapters {
+		end := total
+		if i+1 < len(chapters) {
+			end = chapters[i+1].Offset
+		}
+		chapters[i].Length = end - chapters[i].Offset
+		// Skip headings before this chapter; use one that opens it.
+		for h < len(w.headings) && w.headings[h].offset < chapters[i].Offset {
+			h++
+		}
+		if h < len(w.headings) && w.headings[h].offset < end && w.headings[h].offset-chapters[i].Offset <= 2 {
+			chapters[i].Title = w.headings[h].text
+		}
+	}
+	return chapters
+}
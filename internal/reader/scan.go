@@ -0,0 +1,61 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ScanDirectory lists the files under root whose extension is one of
+// extensions (lowercase, with the leading dot, as returned by
+// Reader.Extensions). depth limits recursion: 0 scans only root, 1 also
+// scans its immediate subdirectories, and so on. Hidden directories and
+// symlinked directories are skipped, the latter so that link cycles
+// cannot cause endless scanning.
+//
+// An error is returned only if root itself cannot be read; unreadable
+// subdirectories are skipped. Paths are returned depth-first, with the
+// entries of each directory in name order.
+func ScanDirectory(root string, depth int, extensions []string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	scanEntries(root, entries, max(0, depth), extensions, &out)
+	return out, nil
+}
+
+// scanEntries appends matching files from one directory's entries to
+// out and descends into subdirectories while depth remains.
+func scanEntries(dir string, entries []os.DirEntry, depth int, extensions []string, out *[]string) {
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if e.IsDir() {
+			if depth == 0 || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			sub, err := os.ReadDir(path)
+			if err != nil {
+				continue
+			}
+			scanEntries(path, sub, depth-1, extensions, out)
+			continue
+		}
+		if hasExtension(e.Name(), extensions) {
+			*out = append(*out, path)
+		}
+	}
+}
+
+// hasExtension reports whether name ends in one of extensions,
+// ignoring case.
+func hasExtension(name string, extensions []string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package reader
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// BookStats summarizes the size of a book's text.
+type BookStats struct {
+	Characters int
+	Words      int
+	Chapters   int
+}
+
+// ComputeStats counts the characters (runes), words (runs of
+// non-whitespace) and chapters of book.
+func ComputeStats(book LoadedBook) BookStats {
+	words := 0
+	inWord := false
+	for _, r := range book.Text {
+		space := unicode.IsSpace(r)
+		if !space && !inWord {
+			words++
+		}
+		inWord = !space
+	}
+	return BookStats{
+		Characters: utf8.RuneCountInString(book.Text),
+		Words:      words,
+		Chapters:   len(book.Book.Chapters),
+	}
+}
+
+// ReadingMinutes estimates the minutes needed to read the words at wpm
+// words per minute, rounded up.
+func (s BookStats) ReadingMinutes(wpm int) int {
+	if wpm <= 0 {
+		return 0
+	}
+	return (s.Words + wpm - 1) / wpm
+}
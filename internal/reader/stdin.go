@@ -0,0 +1,115 @@
+package reader
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// SniffExtension guesses the format of a book from its leading bytes
+// and returns the extension its reader is registered under: ".epub"
+// for a ZIP container with an EPUB mimetype entry, ".zip" for other ZIP
+// archives, ".mobi" for a MobiPocket PalmDB, ".fb2" for XML and ".txt"
+// for other valid UTF-8 text. It returns "" if the format cannot be
+// recognized.
+func SniffExtension(data []byte) string {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		if isEPUBArchive(data) {
+			return ".epub"
+		}
+		return ".zip"
+	}
+	if len(data) >= 68 && string(data[60:68]) == "BOOKMOBI" {
+		return ".mobi"
+	}
+	head := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF")), " \t\r\n")
+	if bytes.HasPrefix(head, []byte("<?xml")) || bytes.HasPrefix(head, []byte("<FictionBook")) {
+		return ".fb2"
+	}
+	if utf8.Valid(data) {
+		return ".txt"
+	}
+	return ""
+}
+
+// isEPUBArchive reports whether the ZIP archive data holds an EPUB:
+// one whose "mimetype" entry reads application/epub+zip. EPUBs store
+// that entry first and uncompressed, so it can be recognized from the
+// leading bytes alone; otherwise the whole archive is searched, which
+// needs all of data.
+func isEPUBArchive(data []byte) bool {
+	const mimetype = "application/epub+zip"
+	if len(data) >= 30 {
+		nameLen := int(binary.LittleEndian.Uint16(data[26:]))
+		extraLen := int(binary.LittleEndian.Uint16(data[28:]))
+		name := data[30:min(len(data), 30+nameLen)]
+		content := data[min(len(data), 30+nameLen+extraLen):]
+		if string(name) == "mimetype" && bytes.HasPrefix(content, []byte(mimetype)) {
+			return true
+		}
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return false
+	}
+	for _, f := range zr.File {
+		if f.Name != "mimetype" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return false
+		}
+		defer rc.Close()
+		content, err := io.ReadAll(io.LimitReader(rc, 64))
+		return err == nil && strings.TrimSpace(string(content)) == mimetype
+	}
+	return false
+}
+
+// OpenStream loads a book from r, e.g. standard input, detecting its
+// format with SniffExtension. Since readers work on files, the content
+// is spooled to a temporary file named name plus the sniffed extension,
+// which is removed again before returning. The returned book has no
+// SourcePath, as it cannot be reopened. Where the reader would have used
+// the temporary path as the book's ID, a hash of the content is used
+// instead, so that reading the same book again finds its saved state.
+func (u UnifiedReader) OpenStream(r io.Reader, name string) (LoadedBook, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	ext := SniffExtension(data)
+	if ext == "" {
+		return LoadedBook{}, fmt.Errorf("%w: %s: unrecognized content", ErrUnsupportedFormat, name)
+	}
+
+	dir, err := os.MkdirTemp("", "thujareader-")
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, name+ext)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return LoadedBook{}, err
+	}
+
+	book, err := u.Open(path)
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	book.SourcePath = ""
+	if strings.HasPrefix(string(book.Book.ID), dir) {
+		sum := sha256.Sum256(data)
+		book.Book.ID = BookID("sha256:" + hex.EncodeToString(sum[:]))
+	}
+	return book, nil
+}
@@ -0,0 +1,94 @@
+package reader
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// zipArchive returns a ZIP archive of the given entries, in order; the
+// "mimetype" entry is stored uncompressed, as in an EPUB.
+func zipArchive(t *testing.T, entries ...[2]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e[0], Method: zip.Deflate}
+		if e[0] == "mimetype" {
+			header.Method = zip.Store
+		}
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const minimalFB2 = `<?xml version="1.0" encoding="utf-8"?>
+<FictionBook xmlns="http://www.gribuser.ru/xml/fictionbook/2.0">
+  <description><title-info><book-title>Zipped</book-title></title-info></description>
+  <body><section><title><p>One</p></title><p>Hello from a ZIP.</p></section></body>
+</FictionBook>`
+
+func TestSniffExtension(t *testing.T) {
+	container := [2]string{"META-INF/container.xml", "<container/>"}
+	mimetype := [2]string{"mimetype", "application/epub+zip"}
+	mobi := make([]byte, 80)
+	copy(mobi[60:], "BOOKMOBI")
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"EPUB", zipArchive(t, mimetype, container), ".epub"},
+		{"EPUB with mimetype last", zipArchive(t, container, mimetype), ".epub"},
+		{"zipped FB2", zipArchive(t, [2]string{"book.fb2", minimalFB2}), ".zip"},
+		{"MOBI", mobi, ".mobi"},
+		{"FB2", []byte("\uFEFF" + minimalFB2), ".fb2"},
+		{"text", []byte("Just some text.\n"), ".txt"},
+		{"binary", []byte{0xff, 0xfe, 0x00, 0x81}, ""},
+	}
+	for _, tt := range tests {
+		if got := SniffExtension(tt.data); got != tt.want {
+			t.Errorf("SniffExtension(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOpenStreamZippedFB2(t *testing.T) {
+	data := zipArchive(t, [2]string{"book.fb2", minimalFB2})
+	book, err := NewDefaultUnifiedReader().OpenStream(bytes.NewReader(data), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if book.Book.Title != "Zipped" || !bytes.Contains([]byte(book.Text), []byte("Hello from a ZIP.")) {
+		t.Errorf("got title %q and text %q", book.Book.Title, book.Text)
+	}
+	if book.SourcePath != "" {
+		t.Errorf("SourcePath = %q, want none for a stream", book.SourcePath)
+	}
+}
+
+func TestOpenStreamStableID(t *testing.T) {
+	u := NewDefaultUnifiedReader()
+	var ids [2]BookID
+	for i := range ids {
+		book, err := u.OpenStream(strings.NewReader("Piped text.\n"), "stdin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = book.Book.ID
+	}
+	if ids[0] != ids[1] || strings.Contains(string(ids[0]), os.TempDir()) {
+		t.Errorf("IDs %q and %q, want the same content-derived ID", ids[0], ids[1])
+	}
+}
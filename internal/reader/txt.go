@@ -0,0 +1,81 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// TXTReader loads plain UTF-8 text files. Paragraphs separated by one
+// or more blank lines become untitled chapters, so positions and
+// progress work as for structured formats.
+type TXTReader struct{}
+
+// NewTXTReader returns a reader for .txt files.
+func NewTXTReader() TXTReader {
+	return TXTReader{}
+}
+
+// Extensions implements Reader.
+func (TXTReader) Extensions() []string {
+	return []string{".txt"}
+}
+
+// Open implements Reader. The book title is the file name without its
+// extension; the book ID is the absolute path, since plain text carries
+// no identifier of its own.
+func (TXTReader) Open(path string) (LoadedBook, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	if !utf8.Valid(data) {
+		return LoadedBook{}, ErrInvalidUTF8
+	}
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	id := path
+	if abs, err := filepath.Abs(path); err == nil {
+		id = abs
+	}
+	book := Book{
+		ID:              BookID(id),
+		Title:           strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Chapters:        paragraphChapters(text),
+		TotalCharacters: utf8.RuneCountInString(text),
+	}
+	return LoadedBook{Book: book, Text: text}, nil
+}
+
+// paragraphChapters splits text into one chapter per paragraph. Each
+// chapter starts at the first line of its paragraph and runs up to the
+// next paragraph, so the blank lines in between belong to the preceding
+// chapter and the chapters tile the text exactly. Leading blank lines
+// belong to the first chapter.
+func paragraphChapters(text string) []Chapter {
+	var chapters []Chapter
+	offset := 0 // rune offset of the current line
+	inParagraph := false
+	for _, line := range strings.SplitAfter(text, "\n") {
+		blank := strings.TrimSpace(line) == ""
+		if !blank && !inParagraph {
+			start := offset
+			if len(chapters) == 0 {
+				start = 0
+			}
+			chapters = append(chapters, Chapter{Index: len(chapters), Offset: start})
+		}
+		inParagraph = !blank
+		offset += utf8.RuneCountInString(line)
+	}
+	for i := range chapters {
+		end := offset
+		if i+1 < len(chapters) {
+			end = chapters[i+1].Offset
+		}
+		chapters[i].Length = end - chapters[i].Offset
+	}
+	return chapters
+}
@@ -0,0 +1,64 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTXTReaderParagraphs(t *testing.T) {
+	fixture := "\uFEFF\r\n\r\nFirst paragraph,\r\nstill the first.\r\n\r\n\r\nSecond paragraph — ünïcode.\r\n   \r\nThird.\r\n"
+	path := filepath.Join(t.TempDir(), "story.txt")
+	if err := os.WriteFile(path, []byte(fixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	book, err := NewTXTReader().Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantText := "\n\nFirst paragraph,\nstill the first.\n\n\nSecond paragraph — ünïcode.\n   \nThird.\n"
+	if book.Text != wantText {
+		t.Errorf("Text = %q, want %q", book.Text, wantText)
+	}
+	if book.Book.Title != "story" {
+		t.Errorf("Title = %q, want %q", book.Book.Title, "story")
+	}
+
+	// The leading blank lines belong to the first chapter, and the blank
+	// lines after a paragraph to the chapter before them.
+	runes := []rune(book.Text)
+	wantChapters := []string{
+		"\n\nFirst paragraph,\nstill the first.\n\n\n",
+		"Second paragraph — ünïcode.\n   \n",
+		"Third.\n",
+	}
+	if len(book.Book.Chapters) != len(wantChapters) {
+		t.Fatalf("got %d chapters, want %d: %+v", len(book.Book.Chapters), len(wantChapters), book.Book.Chapters)
+	}
+	next := 0
+	for i, ch := range book.Book.Chapters {
+		if ch.Index != i || ch.Offset != next {
+			t.Errorf("chapter %d: index %d, offset %d; want %d, %d", i, ch.Index, ch.Offset, i, next)
+		}
+		if got := string(runes[ch.Offset : ch.Offset+ch.Length]); got != wantChapters[i] {
+			t.Errorf("chapter %d = %q, want %q", i, got, wantChapters[i])
+		}
+		next = ch.Offset + ch.Length
+	}
+	if total := utf8.RuneCountInString(book.Text); next != total || book.Book.TotalCharacters != total {
+		t.Errorf("chapters end at %d and TotalCharacters is %d, want %d", next, book.Book.TotalCharacters, total)
+	}
+	if err := ValidateLoadedBook(book); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParagraphChaptersEmpty(t *testing.T) {
+	for _, text := range []string{"", "\n\n", "  \n\t\n"} {
+		if chapters := paragraphChapters(text); len(chapters) != 0 {
+			t.Errorf("paragraphChapters(%q) = %+v, want none", text, chapters)
+		}
+	}
+}
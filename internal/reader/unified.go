@@ -0,0 +1,197 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnsupportedFormat is returned by UnifiedReader.Open when no
+// registered Reader handles the file's extension.
+var ErrUnsupportedFormat = errors.New("unsupported book format")
+
+// UnifiedReader is the single entry point for loading books regardless
+// of format. It dispatches on the file extension to one of its readers,
+// in registration order, and otherwise asks its plugins.
+type UnifiedReader struct {
+	readers []Reader
+	plugins []ReaderPlugin
+}
+
+// NewUnifiedReader returns a UnifiedReader dispatching to the given
+// readers. When several readers claim the same extension, the first one
+// wins.
+func NewUnifiedReader(readers ...Reader) UnifiedReader {
+	return UnifiedReader{readers: readers}
+}
+
+// NewDefaultUnifiedReader returns a UnifiedReader with all built-in
+// format readers registered, plus ZIP archives of books in those
+// formats.
+func NewDefaultUnifiedReader() UnifiedReader {
+	formats := []Reader{NewEPUBReader(), NewFB2Reader(), NewMOBIReader(), NewTXTReader()}
+	return NewUnifiedReader(append(formats, NewZIPReader(formats...))...)
+}
+
+// RegisterPlugin adds a loader that is consulted, in registration
+// order, for files that no built-in reader claims by extension.
+func (u *UnifiedReader) RegisterPlugin(p ReaderPlugin) {
+	u.plugins = append(u.plugins, p)
+}
+
+// Extensions returns every extension handled by the registered readers
+// and plugins, without duplicates, e.g. for ScanDirectory.
+func (u UnifiedReader) Extensions() []string {
+	var out []string
+	seen := make(map[string]bool)
+	add := func(exts []string) {
+		for _, e := range exts {
+			if !seen[e] {
+				seen[e] = true
+				out = append(out, e)
+			}
+		}
+	}
+	for _, r := range u.readers {
+		add(r.Extensions())
+	}
+	for _, p := range u.plugins {
+		if x, ok := p.(interface{ Extensions() []string }); ok {
+			add(x.Extensions())
+		}
+	}
+	return out
+}
+
+// Open loads the book at path using the reader registered for its
+// extension or, failing that, the first plugin that can open it.
+func (u UnifiedReader) Open(path string) (LoadedBook, error) {
+	if IsURL(path) {
+		return u.openURL(path)
+	}
+	if r := u.readerFor(path); r != nil {
+		book, err := r.Open(path)
+		return finishBook(path, book, err)
+	}
+	for _, p := range u.plugins {
+		if p.CanOpen(path) {
+			book, err := p.Open(path)
+			return finishBook(path, book, err)
+		}
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		return LoadedBook{}, fmt.Errorf("%w: %s has no file extension", ErrUnsupportedFormat, filepath.Base(path))
+	}
+	return LoadedBook{}, fmt.Errorf("%w: %s", ErrUnsupportedFormat, ext)
+}
+
+// readerFor returns the reader registered for the extension of path,
+// or nil.
+func (u UnifiedReader) readerFor(path string) Reader {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, r := range u.readers {
+		for _, e := range r.Extensions() {
+			if e == ext {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+// OpensWithPlugin reports whether Open would hand the file at path to
+// a plugin rather than to a built-in reader.
+func (u UnifiedReader) OpensWithPlugin(path string) bool {
+	if u.readerFor(path) != nil {
+		return false
+	}
+	for _, p := range u.plugins {
+		if p.CanOpen(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// finishBook normalizes and validates the result of a loader's Open
+// for the book at path. Chapter extents are repaired first, so that
+// validation checks the extents the rest of the program sees.
+func finishBook(path string, book LoadedBook, err error) (LoadedBook, error) {
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	normalizeChapterTitles(&book.Book)
+	fixChapterExtents(&book)
+	if err := ValidateLoadedBook(book); err != nil {
+		return LoadedBook{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	book.SourcePath = path
+	if len(book.TOC) == 0 {
+		book.TOC = chapterTOC(book.Book)
+	}
+	book.Stats = ComputeStats(book)
+	return book, nil
+}
+
+// chapterTOC builds a table of contents with one entry per titled
+// chapter, for loaders whose format has no explicit TOC.
+func chapterTOC(b Book) []TOCEntry {
+	var toc []TOCEntry
+	for i, ch := range b.Chapters {
+		if ch.Title == "" {
+			continue
+		}
+		toc = append(toc, TOCEntry{
+			Label:  ch.Title,
+			BookID: b.ID,
+			Pos:    Position{ChapterIndex: i},
+		})
+	}
+	return toc
+}
+
+// normalizeChapterTitles trims surrounding whitespace (often left over
+// from markup indentation) from every chapter title, so consumers can
+// test for an empty title directly.
+func normalizeChapterTitles(b *Book) {
+	for i := range b.Chapters {
+		b.Chapters[i].Title = strings.TrimSpace(b.Chapters[i].Title)
+	}
+}
+
+// fixChapterExtents recomputes chapter lengths as exact rune counts so
+// that chapters tile the text without gaps or overlaps: each chapter
+// runs from its offset to the next chapter's offset, and the last one
+// to the end of the text. Loaders that estimate lengths (bytes, words)
+// would otherwise make offset-to-chapter lookups wrong near chapter
+// boundaries. The first chapter is anchored at offset 0 so that any
+// front matter belongs to it.
+func fixChapterExtents(b *LoadedBook) {
+	chapters := b.Book.Chapters
+	if len(chapters) == 0 {
+		return
+	}
+	total := utf8.RuneCountInString(b.Text)
+	prev := 0
+	for i := range chapters {
+		off := chapters[i].Offset
+		if i == 0 || off < prev {
+			off = prev
+		}
+		if off > total {
+			off = total
+		}
+		chapters[i].Offset = off
+		prev = off
+	}
+	for i := range chapters {
+		end := total
+		if i+1 < len(chapters) {
+			end = chapters[i+1].Offset
+		}
+		chapters[i].Length = end - chapters[i].Offset
+	}
+}
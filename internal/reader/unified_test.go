@@ -0,0 +1,56 @@
+package reader
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakePlugin opens files with the extension ext as a one-line book.
+type fakePlugin struct{ ext string }
+
+func (p fakePlugin) CanOpen(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), p.ext)
+}
+
+func (p fakePlugin) Open(path string) (LoadedBook, error) {
+	return LoadedBook{Book: Book{ID: BookID(path)}, Text: "plugin text\n"}, nil
+}
+
+func TestOpensWithPlugin(t *testing.T) {
+	u := NewDefaultUnifiedReader()
+	u.RegisterPlugin(fakePlugin{ext: ".fake"})
+	u.RegisterPlugin(fakePlugin{ext: ".txt"})
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"book.fake", true},
+		{"BOOK.FAKE", true},
+		{"book.txt", false}, // a built-in reader comes first
+		{"book.unknown", false},
+	}
+	for _, tt := range tests {
+		if got := u.OpensWithPlugin(tt.path); got != tt.want {
+			t.Errorf("OpensWithPlugin(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	book, err := u.Open("book.fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if book.Text != "plugin text\n" {
+		t.Errorf("Open(book.fake).Text = %q", book.Text)
+	}
+}
+
+func TestNormalizeChapterTitles(t *testing.T) {
+	b := Book{Chapters: []Chapter{{Title: "  Chapter 1\n"}, {Title: "\t \n"}, {Title: ""}, {Title: "Plain"}}}
+	normalizeChapterTitles(&b)
+	want := []string{"Chapter 1", "", "", "Plain"}
+	for i, ch := range b.Chapters {
+		if ch.Title != want[i] {
+			t.Errorf("chapter %d title = %q, want %q", i, ch.Title, want[i])
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxDownloadSize limits how much of a book is downloaded, so that a
+// wrong URL cannot fill the disk.
+const maxDownloadSize = 256 << 20
+
+// downloadClient fetches books from URLs. The timeout covers the whole
+// download.
+var downloadClient = &http.Client{Timeout: 2 * time.Minute}
+
+// contentTypeExtensions maps the Content-Type of a download to the
+// extension of the reader that handles it. Generic types such as
+// application/octet-stream are left to the URL and the content.
+var contentTypeExtensions = map[string]string{
+	"application/epub+zip":           ".epub",
+	"application/x-fictionbook+xml":  ".fb2",
+	"application/x-fictionbook":      ".fb2",
+	"application/x-mobipocket-ebook": ".mobi",
+	"application/vnd.amazon.ebook":   ".azw3",
+	"text/plain":                     ".txt",
+}
+
+// IsURL reports whether s is an http or https URL rather than a path.
+func IsURL(s string) bool {
+	s = strings.ToLower(s)
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// openURL downloads the book at rawURL and opens it with the reader for
+// its format, taken from the Content-Type header, else from the URL's
+// extension, else from the content itself. A generic text/plain type
+// yields to a known extension. The download is deleted
+// once the book is loaded; the book keeps rawURL as its source, and as
+// its ID where the reader would have used the download's path.
+func (u UnifiedReader) openURL(rawURL string) (LoadedBook, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	resp, err := downloadClient.Get(rawURL)
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return LoadedBook{}, fmt.Errorf("download %s: %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return LoadedBook{}, fmt.Errorf("download %s: %w", rawURL, err)
+	}
+	if len(data) > maxDownloadSize {
+		return LoadedBook{}, fmt.Errorf("download %s: larger than %d MiB", rawURL, maxDownloadSize>>20)
+	}
+
+	name := path.Base(parsed.Path)
+	if name == "." || name == "/" {
+		name = "book"
+	}
+	ext := strings.ToLower(path.Ext(name))
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		// Servers send text/plain for many text formats, such as FB2's
+		// XML, so it does not override a known extension.
+		if e, ok := contentTypeExtensions[mediaType]; ok && !(mediaType == "text/plain" && u.handles(ext)) {
+			ext = e
+		}
+	}
+	if !u.handles(ext) {
+		ext = SniffExtension(data)
+	}
+	if ext == "" {
+		return LoadedBook{}, fmt.Errorf("%w: %s: unrecognized content", ErrUnsupportedFormat, rawURL)
+	}
+
+	dir, err := os.MkdirTemp("", "thujareader-")
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, strings.TrimSuffix(name, path.Ext(name))+ext)
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return LoadedBook{}, err
+	}
+
+	book, err := u.Open(tmp)
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	book.SourcePath = rawURL
+	if id := string(book.Book.ID); id == tmp || strings.HasPrefix(id, dir) {
+		book.Book.ID = BookID(rawURL)
+	}
+	return book, nil
+}
+
+// handles reports whether a registered reader claims ext.
+func (u UnifiedReader) handles(ext string) bool {
+	for _, r := range u.readers {
+		for _, e := range r.Extensions() {
+			if e == ext {
+				return true
+			}
+		}
+	}
+	return false
+}
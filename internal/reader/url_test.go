@@ -0,0 +1,23 @@
+package reader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenURLPrefersExtensionOverTextPlain(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(minimalFB2))
+	}))
+	defer srv.Close()
+
+	book, err := NewDefaultUnifiedReader().Open(srv.URL + "/book.fb2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if book.Book.Title != "Zipped" {
+		t.Errorf("title %q, want the FB2 title; text %q", book.Book.Title, book.Text)
+	}
+}
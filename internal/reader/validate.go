@@ -0,0 +1,46 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTF8 is returned by ValidateLoadedBook when the book text
+// is not valid UTF-8.
+var ErrInvalidUTF8 = errors.New("book text is not valid UTF-8")
+
+// ValidateBook inspects a loaded book for problems that do not prevent
+// reading but degrade navigation, and returns a human-readable warning
+// for each. A nil result means no problems were found.
+func ValidateBook(b LoadedBook) []string {
+	var warnings []string
+	if len(b.TOC) == 0 {
+		warnings = append(warnings, "book has no table of contents")
+	}
+	if b.Book.TotalCharacters == 0 {
+		warnings = append(warnings, "book size is unknown (TotalCharacters is 0)")
+	}
+	return warnings
+}
+
+// ValidateLoadedBook checks the invariants that offset arithmetic on a
+// loaded book relies on and returns an error describing the first
+// violation. Unlike ValidateBook's warnings, a violation means positions
+// and search results would be wrong.
+func ValidateLoadedBook(b LoadedBook) error {
+	if !utf8.ValidString(b.Text) {
+		return ErrInvalidUTF8
+	}
+	if len(b.Book.Chapters) > 0 {
+		total := utf8.RuneCountInString(b.Text)
+		sum := 0
+		for _, ch := range b.Book.Chapters {
+			sum += ch.Length
+		}
+		if sum != total {
+			return fmt.Errorf("chapter lengths sum to %d runes, but the text has %d", sum, total)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package reader
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// maxArchivedBookSize limits the size of a book extracted from a ZIP
+// archive, so that a malformed archive cannot fill the disk.
+const maxArchivedBookSize = 256 << 20
+
+// ZIPReader loads books distributed as ZIP archives holding a single
+// book, such as the common .fb2.zip. The first archived file with an
+// extension one of its readers handles is extracted and opened with
+// that reader.
+type ZIPReader struct {
+	readers []Reader
+}
+
+// NewZIPReader returns a reader for .zip archives of books in the
+// formats of the given readers.
+func NewZIPReader(readers ...Reader) ZIPReader {
+	return ZIPReader{readers: readers}
+}
+
+// Extensions implements Reader.
+func (ZIPReader) Extensions() []string {
+	return []string{".zip"}
+}
+
+// Open implements Reader. The book keeps the ID its reader gives it,
+// except that IDs derived from the path of the extracted file become
+// the archive's absolute path, so they stay the same between opens.
+func (z ZIPReader) Open(archivePath string) (LoadedBook, error) {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return LoadedBook{}, fmt.Errorf("open ZIP: %w", err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		r := z.readerFor(f.Name)
+		if r == nil {
+			continue
+		}
+		if f.UncompressedSize64 > maxArchivedBookSize {
+			return LoadedBook{}, fmt.Errorf("%s in %s: larger than %d MiB", f.Name, filepath.Base(archivePath), maxArchivedBookSize>>20)
+		}
+		return z.openEntry(archivePath, f, r)
+	}
+	return LoadedBook{}, fmt.Errorf("%w: %s contains no book", ErrUnsupportedFormat, filepath.Base(archivePath))
+}
+
+// readerFor returns the reader for an archived file name, or nil.
+func (z ZIPReader) readerFor(name string) Reader {
+	ext := strings.ToLower(path.Ext(name))
+	for _, r := range z.readers {
+		for _, e := range r.Extensions() {
+			if e == ext {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+// openEntry extracts f to a temporary file, opens it with r and
+// removes it again.
+func (z ZIPReader) openEntry(archivePath string, f *zip.File, r Reader) (LoadedBook, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return LoadedBook{}, fmt.Errorf("%s in %s: %w", f.Name, filepath.Base(archivePath), err)
+	}
+	defer rc.Close()
+
+	dir, err := os.MkdirTemp("", "thujareader-")
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, path.Base(f.Name))
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	_, err = io.Copy(out, io.LimitReader(rc, maxArchivedBookSize))
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return LoadedBook{}, fmt.Errorf("%s in %s: %w", f.Name, filepath.Base(archivePath), err)
+	}
+
+	book, err := r.Open(tmp)
+	if err != nil {
+		return LoadedBook{}, err
+	}
+	if strings.HasPrefix(string(book.Book.ID), dir) {
+		id := archivePath
+		if abs, err := filepath.Abs(archivePath); err == nil {
+			id = abs
+		}
+		book.Book.ID = BookID(id)
+	}
+	return book, nil
+}
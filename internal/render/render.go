@@ -0,0 +1,327 @@
+// Package render draws the rows of the bordered main area. Every
+// renderer returns exactly visibleHeight rows, each exactly innerWidth
+// terminal cells wide, so callers only need to add the borders. The
+// functions depend on plain values rather than the UI model, which keeps
+// each of them testable in isolation.
+package render
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Decor carries the theme-dependent details renderers need. The UI
+// derives it from its Theme; it lives here so that this package does
+// not import the ui package (which imports render).
+type Decor struct {
+	ScrollbarThumb rune
+	ScrollbarTrack rune
+
+	// Dim styles secondary text such as a missing-file marker. A nil
+	// Dim leaves the text unstyled.
+	Dim func(string) string
+}
+
+// dim applies the Dim style if one is set.
+func (d Decor) dim(text string) string {
+	if d.Dim == nil {
+		return text
+	}
+	return d.Dim(text)
+}
+
+// RecentFile is one entry of the Recent Files dialog. A zero ModTime
+// marks a file that no longer exists.
+type RecentFile struct {
+	Path    string
+	ModTime time.Time
+}
+
+// ShelfBook is one row of the bookshelf. A zero LastOpened marks a book
+// opened before the bookshelf kept track of it, whose details are
+// unknown.
+type ShelfBook struct {
+	Title      string
+	Author     string
+	Progress   float64
+	LastOpened time.Time
+}
+
+// ShelfTitleWidth is the most cells of a title the bookshelf shows.
+const ShelfTitleWidth = 40
+
+// RenderBookshelf renders books as a table below a header row: title,
+// author, progress and last-opened time, with the selected book marked.
+// Rows scroll from top; the author column takes the width the others
+// leave.
+func RenderBookshelf(header [4]string, books []ShelfBook, selected, top, visibleHeight, innerWidth int, d Decor) []string {
+	const progressWidth, dateWidth = 8, 16
+	titleWidth := min(ShelfTitleWidth, max(0, innerWidth-2-progressWidth-dateWidth-3))
+	authorWidth := max(0, innerWidth-2-titleWidth-progressWidth-dateWidth-3)
+	row := func(marker, title, author, progress, date string) string {
+		line := marker + PadOrTrim(runewidth.Truncate(title, titleWidth, "…"), titleWidth) + " " +
+			PadOrTrim(runewidth.Truncate(author, authorWidth, "…"), authorWidth) + " " +
+			PadOrTrim(progress, progressWidth) + " " + date
+		return PadOrTrim(line, innerWidth)
+	}
+
+	rows := make([]string, visibleHeight)
+	if visibleHeight == 0 {
+		return rows
+	}
+	rows[0] = d.dim(row("  ", header[0], header[1], header[2], header[3]))
+	for i := 1; i < visibleHeight; i++ {
+		idx := top + i - 1
+		if idx < 0 || idx >= len(books) {
+			rows[i] = strings.Repeat(" ", max(0, innerWidth))
+			continue
+		}
+		b := books[idx]
+		marker := "  "
+		if idx == selected {
+			marker = "> "
+		}
+		progress, date := "", ""
+		if !b.LastOpened.IsZero() {
+			progress = strconv.Itoa(int(b.Progress*100)) + "%"
+			date = b.LastOpened.Local().Format("2006-01-02 15:04")
+		}
+		rows[i] = row(marker, b.Title, b.Author, progress, date)
+	}
+	return rows
+}
+
+// RenderContent renders the wrapped book lines visible from topLine.
+func RenderContent(lines []string, topLine, visibleHeight, innerWidth int) []string {
+	rows := make([]string, visibleHeight)
+	for i := range rows {
+		idx := topLine + i
+		if idx >= 0 && idx < len(lines) {
+			rows[i] = PadOrTrim(lines[idx], innerWidth)
+		} else {
+			rows[i] = strings.Repeat(" ", max(0, innerWidth))
+		}
+	}
+	return rows
+}
+
+// MenuEntry is one item of a menu dropdown: its label and an optional
+// keyboard hint such as "F3".
+type MenuEntry struct {
+	Label string
+	Hint  string
+}
+
+// RenderMenuDropdown renders the items of an open menu at the top of
+// the main area, marking the active item with a leading ">". Hints are
+// aligned in a column after the widest label, as in edit.exe.
+func RenderMenuDropdown(entries []MenuEntry, active, visibleHeight, innerWidth int) []string {
+	labelWidth := 0
+	for _, e := range entries {
+		labelWidth = max(labelWidth, runewidth.StringWidth(e.Label))
+	}
+
+	rows := make([]string, visibleHeight)
+	for i := range rows {
+		if i >= len(entries) {
+			rows[i] = strings.Repeat(" ", max(0, innerWidth))
+			continue
+		}
+		line := entries[i].Label
+		if entries[i].Hint != "" {
+			line = PadOrTrim(line, labelWidth) + "  " + entries[i].Hint
+		}
+		// Other items are padded with a space for alignment.
+		if i == active {
+			rows[i] = PadOrTrim(">"+line, innerWidth)
+		} else {
+			rows[i] = PadOrTrim(" "+line, innerWidth)
+		}
+	}
+	return rows
+}
+
+// RenderTOCDialog renders the TOC entries starting at top with the
+// selected one marked. When the list does not fit, the rightmost column
+// holds a scrollbar. The row after the list shows the selected entry's
+// position, e.g. "3/120".
+func RenderTOCDialog(labels []string, selected, top, visibleHeight, innerWidth int, d Decor) []string {
+	rows := make([]string, visibleHeight)
+	listRows := max(1, visibleHeight-1)
+	scrollable := len(labels) > listRows
+	labelWidth := innerWidth
+	if scrollable {
+		labelWidth = max(0, innerWidth-1)
+	}
+
+	for i := range rows {
+		switch {
+		case i < listRows:
+			rows[i] = PadOrTrim(markSelected(labels, top+i, selected), labelWidth)
+			if scrollable && innerWidth > 0 {
+				rows[i] += string(ScrollbarCell(i, listRows, top, len(labels), d))
+			}
+		case i == listRows:
+			counter := itoa(selected+1) + "/" + itoa(len(labels)) + " "
+			rows[i] = PadOrTrim(strings.Repeat(" ", max(0, innerWidth-runewidth.StringWidth(counter)))+counter, innerWidth)
+		default:
+			rows[i] = strings.Repeat(" ", max(0, innerWidth))
+		}
+	}
+	return rows
+}
+
+// RenderFileBrowserDialog renders the Open dialog: the browsed
+// directory on the first row (shortened from the left if needed) and
+// the entries below it as in RenderTOCDialog. An empty listing shows
+// the empty text instead.
+func RenderFileBrowserDialog(dir string, labels []string, selected, top, visibleHeight, innerWidth int, empty string, d Decor) []string {
+	if visibleHeight <= 0 {
+		return nil
+	}
+	rows := []string{PadOrTrim(ShortenPath(dir, innerWidth), innerWidth)}
+	if len(labels) == 0 {
+		for len(rows) < visibleHeight {
+			rows = append(rows, strings.Repeat(" ", max(0, innerWidth)))
+		}
+		if visibleHeight > 1 {
+			rows[1] = PadOrTrim("  "+empty, innerWidth)
+		}
+		return rows
+	}
+	return append(rows, RenderTOCDialog(labels, selected, top, visibleHeight-1, innerWidth, d)...)
+}
+
+// RenderBookmarksDialog renders the bookmark names starting at top with
+// the selected one marked.
+func RenderBookmarksDialog(names []string, selected, top, visibleHeight, innerWidth int) []string {
+	rows := make([]string, visibleHeight)
+	for i := range rows {
+		rows[i] = PadOrTrim(markSelected(names, top+i, selected), innerWidth)
+	}
+	return rows
+}
+
+// RenderRecentFilesDialog renders one recent file per row, starting at
+// top: the path on the left and its modification date (or a dimmed
+// "[missing]" marker) right-aligned. Paths that do not fit are
+// shortened with ShortenPath so the file name stays visible.
+func RenderRecentFilesDialog(files []RecentFile, selected, top, visibleHeight, innerWidth int, d Decor) []string {
+	rows := make([]string, visibleHeight)
+	for i := range rows {
+		idx := top + i
+		if idx < 0 || idx >= len(files) {
+			rows[i] = strings.Repeat(" ", max(0, innerWidth))
+			continue
+		}
+		marker := "  "
+		if idx == selected {
+			marker = "> "
+		}
+
+		right := "[missing]"
+		missing := files[idx].ModTime.IsZero()
+		if !missing {
+			right = files[idx].ModTime.Format("2006-01-02")
+		}
+		rightWidth := runewidth.StringWidth(right)
+		if innerWidth < rightWidth+2 {
+			rows[i] = PadOrTrim(marker+ShortenPath(files[idx].Path, innerWidth-2), innerWidth)
+			continue
+		}
+		if missing {
+			right = d.dim(right)
+		}
+		labelWidth := innerWidth - rightWidth - 1
+		rows[i] = PadOrTrim(marker+ShortenPath(files[idx].Path, labelWidth-2), labelWidth) + " " + right
+	}
+	return rows
+}
+
+// ShortenPath fits path into width display cells while keeping the
+// file name visible: the directory part is cut from the left and
+// replaced by "…". If even the file name does not fit, it is cut from
+// the right instead.
+func ShortenPath(path string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	pathWidth := runewidth.StringWidth(path)
+	if pathWidth <= width {
+		return path
+	}
+	if runewidth.StringWidth(filepath.Base(path))+1 > width {
+		return runewidth.Truncate(filepath.Base(path), width, "…")
+	}
+	return "…" + runewidth.TruncateLeft(path, pathWidth-(width-1), "")
+}
+
+// ScrollbarCell returns the scrollbar character for the given row of a
+// list viewport showing rows entries starting at top out of total.
+func ScrollbarCell(row, rows, top, total int, d Decor) rune {
+	if rows <= 0 || total <= rows {
+		return d.ScrollbarTrack
+	}
+	thumbSize := max(1, rows*rows/total)
+	thumbStart := top * (rows - thumbSize) / (total - rows)
+	if row >= thumbStart && row < thumbStart+thumbSize {
+		return d.ScrollbarThumb
+	}
+	return d.ScrollbarTrack
+}
+
+// markSelected returns items[idx] prefixed with "> " when it is the
+// selected entry and with two spaces otherwise, or "" when idx is out
+// of range.
+func markSelected(items []string, idx, selected int) string {
+	if idx < 0 || idx >= len(items) {
+		return ""
+	}
+	if idx == selected {
+		return "> " + items[idx]
+	}
+	return "  " + items[idx]
+}
+
+// PadOrTrim returns a version of s whose printable width (in terminal
+// cells) is exactly width, using runewidth to account for multi-byte
+// and wide runes. If s is wider, it is truncated; if it is narrower,
+// it is padded with spaces on the right.
+func PadOrTrim(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	w := runewidth.StringWidth(s)
+	if w > width {
+		return runewidth.Truncate(s, width, "")
+	}
+	if w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// PadOrTrimRTL is PadOrTrim for right-to-left text: it pads on the left
+// so the text is right-aligned, and trims from the left.
+func PadOrTrimRTL(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	w := runewidth.StringWidth(s)
+	if w > width {
+		return runewidth.TruncateLeft(s, w-width, "")
+	}
+	if w < width {
+		return strings.Repeat(" ", width-w) + s
+	}
+	return s
+}
+
+// itoa is a small helper for integer-to-string conversion.
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
@@ -0,0 +1,52 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestRenderMenuDropdownWideLabels(t *testing.T) {
+	entries := []MenuEntry{
+		{Label: "打开文件...", Hint: "F3"}, // double-width label
+		{Label: "Über", Hint: "F1"},
+		{Label: "Beenden"},
+	}
+	const innerWidth = 30
+	rows := RenderMenuDropdown(entries, 1, 5, innerWidth)
+	if len(rows) != 5 {
+		t.Fatalf("got %d rows, want 5", len(rows))
+	}
+	for i, row := range rows {
+		if w := runewidth.StringWidth(row); w != innerWidth {
+			t.Errorf("row %d %q is %d cells wide, want %d", i, row, w, innerWidth)
+		}
+	}
+	if !strings.HasPrefix(rows[1], ">Über") || !strings.HasPrefix(rows[0], " 打开文件...") {
+		t.Errorf("rows %q, want the active item marked with >", rows[:2])
+	}
+
+	// The hints line up after the widest label, counted in cells.
+	hintColumn := func(row, hint string) int {
+		return runewidth.StringWidth(row[:strings.Index(row, hint)])
+	}
+	if a, b := hintColumn(rows[0], "F3"), hintColumn(rows[1], "F1"); a != b {
+		t.Errorf("hints start in columns %d and %d: %q", a, b, rows[:2])
+	}
+}
+
+func TestRenderBookmarksDialogFromTop(t *testing.T) {
+	rows := RenderBookmarksDialog([]string{"a", "b", "c", "d"}, 3, 2, 2, 10)
+	if rows[0] != PadOrTrim("  c", 10) || rows[1] != PadOrTrim("> d", 10) {
+		t.Errorf("rows = %q, want c and the selected d", rows)
+	}
+}
+
+func TestRenderRecentFilesDialogFromTop(t *testing.T) {
+	files := []RecentFile{{Path: "a"}, {Path: "b"}, {Path: "c"}}
+	rows := RenderRecentFilesDialog(files, 2, 1, 2, 30, Decor{})
+	if !strings.HasPrefix(rows[0], "  b") || !strings.HasPrefix(rows[1], "> c") {
+		t.Errorf("rows = %q, want b and the selected c", rows)
+	}
+}
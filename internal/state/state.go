@@ -0,0 +1,135 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"thujareader/internal/atomicfile"
+	"thujareader/internal/reader"
+)
+
+// AppState is the persisted application state: data the user produces
+// while reading, as opposed to settings in config.Config. It is stored
+// as a single JSON document; unknown fields are ignored on load so
+// older binaries can read files written by newer ones.
+type AppState struct {
+	// Bookmarks maps a book ID to the bookmarks created in that book.
+	Bookmarks map[string][]reader.Bookmark `json:"bookmarks,omitempty"`
+
+	// Notes maps a book ID to the notes attached to that book.
+	Notes map[string][]reader.Note `json:"notes,omitempty"`
+
+	// Positions maps a book ID to the last reading position in that
+	// book, so reopening it resumes where the user left off.
+	Positions map[string]reader.Position `json:"positions,omitempty"`
+
+	// RecentFiles lists recently opened book paths, most recent first.
+	RecentFiles []string `json:"recent_files,omitempty"`
+
+	// LastOpenedBookPath is the book that was open when the application
+	// last exited; it is reopened on startup when no path is given.
+	LastOpenedBookPath string `json:"last_opened_book_path,omitempty"`
+
+	// OpenBookPaths lists the books open in tabs at the last exit, in
+	// tab order; LastOpenedBookPath is the active one.
+	OpenBookPaths []string `json:"open_book_paths,omitempty"`
+
+	// Library maps the path of each book opened so far to what the
+	// bookshelf shows about it.
+	Library map[string]BookInfo `json:"library,omitempty"`
+
+	// DailyProgress maps a local date, as "2006-01-02", to the number of
+	// characters read that day.
+	DailyProgress map[string]int `json:"daily_progress,omitempty"`
+}
+
+// BookInfo describes a book on the bookshelf without loading it.
+type BookInfo struct {
+	Title  string `json:"title,omitempty"`
+	Author string `json:"author,omitempty"`
+
+	// LastOpened is when the book was last shown.
+	LastOpened time.Time `json:"last_opened"`
+
+	// Progress is the fraction of the book read, from 0 to 1, as of the
+	// last reading position.
+	Progress float64 `json:"progress"`
+}
+
+// NewAppState returns an empty state with all maps initialized.
+func NewAppState() AppState {
+	return AppState{
+		Bookmarks:     make(map[string][]reader.Bookmark),
+		Notes:         make(map[string][]reader.Note),
+		Positions:     make(map[string]reader.Position),
+		Library:       make(map[string]BookInfo),
+		DailyProgress: make(map[string]int),
+	}
+}
+
+// FileStore loads and saves AppState as a JSON file on disk.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore returns a store backed by the JSON file at path.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load reads the state file. A missing or empty file yields an empty
+// state with a nil error. If the file is present but cannot be read or
+// decoded, an empty (but usable) state is returned together with the
+// error so callers can warn and continue.
+func (s *FileStore) Load() (AppState, error) {
+	if s.path == "" {
+		return NewAppState(), errors.New("state path is empty")
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewAppState(), nil
+		}
+		return NewAppState(), err
+	}
+	if len(data) == 0 {
+		return NewAppState(), nil
+	}
+
+	st := NewAppState()
+	if err := json.Unmarshal(data, &st); err != nil {
+		return NewAppState(), err
+	}
+	if st.Bookmarks == nil {
+		st.Bookmarks = make(map[string][]reader.Bookmark)
+	}
+	if st.Notes == nil {
+		st.Notes = make(map[string][]reader.Note)
+	}
+	return st, nil
+}
+
+// Save writes the state to disk as JSON, creating the parent directory
+// if needed. The file holds personal reading history, so unlike the
+// config file it is only readable by its owner. The file is replaced
+// atomically, so a crash while saving leaves the previous state intact.
+func (s *FileStore) Save(st AppState) error {
+	if s.path == "" {
+		return errors.New("state path is empty")
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(s.path, data, 0o600)
+}
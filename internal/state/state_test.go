@@ -0,0 +1,59 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"thujareader/internal/reader"
+)
+
+func TestFileStoreSaveMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no Unix file modes")
+	}
+	dir := filepath.Join(t.TempDir(), "data")
+	path := filepath.Join(dir, "state.json")
+	store := NewFileStore(path)
+
+	st := NewAppState()
+	st.Bookmarks["book"] = []reader.Bookmark{{Name: "Start", BookID: "book"}}
+	checkSave := func(when string) {
+		t.Helper()
+		if err := store.Save(st); err != nil {
+			t.Fatalf("%s: Save: %v", when, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mode := info.Mode().Perm(); mode != 0o600 {
+			t.Errorf("%s: state file mode %#o, want 0600", when, mode)
+		}
+		// The temporary file is renamed over the state file.
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Errorf("%s: directory holds %d files, want only the state file", when, len(entries))
+		}
+	}
+
+	checkSave("new file")
+
+	// A file left world-readable by an earlier version is tightened.
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checkSave("existing file")
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Bookmarks["book"]; len(got) != 1 || got[0].Name != "Start" {
+		t.Errorf("Load after Save: bookmarks %+v", loaded.Bookmarks)
+	}
+}
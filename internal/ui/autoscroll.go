@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"thujareader/internal/i18n"
+)
+
+// autoScrollTickMsg advances auto-scroll by one line; gen is the
+// autoScrollGen of the run that scheduled it.
+type autoScrollTickMsg struct{ gen int }
+
+// toggleAutoScroll starts auto-scroll, or stops it when it is running.
+func (m *Model) toggleAutoScroll() {
+	if m.autoScroll {
+		m.stopAutoScroll()
+		return
+	}
+	if m.currentBook == nil {
+		m.setStatus(m.tr(i18n.MsgAutoScrollNoBook))
+		return
+	}
+	m.autoScroll = true
+	m.autoScrollGen++
+	m.setStatus(m.tr(i18n.MsgAutoScrollOn))
+	m.scheduleAutoScroll()
+}
+
+// stopAutoScroll stops auto-scroll; a tick already scheduled is ignored
+// when it arrives.
+func (m *Model) stopAutoScroll() {
+	m.autoScroll = false
+	m.setStatus(m.tr(i18n.MsgAutoScrollOff))
+}
+
+// scheduleAutoScroll queues the next tick of the current run.
+func (m *Model) scheduleAutoScroll() {
+	gen := m.autoScrollGen
+	m.queueCmd(tea.Tick(m.autoScrollDelay, func(time.Time) tea.Msg {
+		return autoScrollTickMsg{gen: gen}
+	}))
+}
+
+// handleAutoScrollTick scrolls one line and schedules the next tick,
+// stopping once the last page is shown.
+func (m *Model) handleAutoScrollTick(msg autoScrollTickMsg) {
+	if !m.autoScroll || msg.gen != m.autoScrollGen {
+		return
+	}
+	if m.currentBook == nil || m.topLine >= len(m.lines)-m.visibleLineCount() {
+		m.autoScroll = false
+		m.setStatus(m.tr(i18n.MsgAutoScrollEnd))
+		return
+	}
+	m.scrollBy(1)
+	m.scheduleAutoScroll()
+}
+
+// isArrowKey reports whether msg is one of the four arrow keys.
+func isArrowKey(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyUp, tea.KeyDown, tea.KeyLeft, tea.KeyRight:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"thujareader/internal/i18n"
+	"thujareader/internal/reader"
+)
+
+// exportedBookmark is one entry of a bookmarks export.
+type exportedBookmark struct {
+	Name            string `json:"name"`
+	Chapter         string `json:"chapter"`
+	ChapterIndex    int    `json:"chapter_index"`
+	OffsetInChapter int    `json:"offset_in_chapter"`
+	Percent         int    `json:"percent"`
+}
+
+// defaultExportPath suggests a file name for exporting the open book's
+// bookmarks, next to the book when its location is known.
+func (m Model) defaultExportPath() string {
+	name := "bookmarks.md"
+	if m.currentBook == nil {
+		return name
+	}
+	if title := strings.TrimSpace(m.currentBook.Book.Title); title != "" {
+		name = title + " bookmarks.md"
+	}
+	return m.besideBook(name)
+}
+
+// besideBook returns the path of a file with the given name in the open
+// book's directory, or name alone when the book is not a local file.
+func (m Model) besideBook(name string) string {
+	if m.currentBook.SourcePath != "" && !reader.IsURL(m.currentBook.SourcePath) {
+		return filepath.Join(filepath.Dir(m.currentBook.SourcePath), name)
+	}
+	return name
+}
+
+// exportBookmarks writes the open book's bookmarks to path: as a JSON
+// array when path ends in .json and as Markdown otherwise.
+func (m *Model) exportBookmarks(path string) {
+	current := m.currentBookmarks()
+	if m.currentBook == nil || len(current) == 0 {
+		m.setStatus(m.tr(i18n.MsgBookmarksEmpty))
+		return
+	}
+	if path == "" {
+		m.setStatus(m.tr(i18n.MsgNoPath))
+		return
+	}
+
+	entries := make([]exportedBookmark, len(current))
+	for i, b := range current {
+		percent, _ := m.percentAt(b.Pos)
+		entries[i] = exportedBookmark{
+			Name:            b.Name,
+			Chapter:         m.chapterLabel(b.Pos.ChapterIndex),
+			ChapterIndex:    b.Pos.ChapterIndex,
+			OffsetInChapter: b.Pos.OffsetInChapter,
+			Percent:         percent,
+		}
+	}
+
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var err error
+		if data, err = json.MarshalIndent(entries, "", "  "); err != nil {
+			m.setStatus(m.tr(i18n.MsgExportFailed, err))
+			return
+		}
+		data = append(data, '\n')
+	} else {
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %s\n\n", m.currentBook.Book.Title)
+		for _, e := range entries {
+			fmt.Fprintf(&b, "## %s – %s (%d%%)\n\n", e.Chapter, e.Name, e.Percent)
+		}
+		data = []byte(b.String())
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		m.setStatus(m.tr(i18n.MsgExportFailed, err))
+		return
+	}
+	m.setTransientStatus(m.tr(i18n.MsgBookmarksExported, len(entries), path))
+}
+
+// chapterLabel names the chapter at index i by its title, or by its
+// number when it is untitled.
+func (m Model) chapterLabel(i int) string {
+	if m.currentBook != nil && i >= 0 && i < len(m.currentBook.Book.Chapters) {
+		if title := m.currentBook.Book.Chapters[i].Title; title != "" {
+			return title
+		}
+	}
+	return m.tr(i18n.LabelChapter, i+1)
+}
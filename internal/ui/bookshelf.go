@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"maps"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"thujareader/internal/i18n"
+	"thujareader/internal/render"
+	"thujareader/internal/state"
+)
+
+// SetLibrary installs the persisted bookshelf details, keyed by book
+// path. Details of books already opened in this session are kept.
+func (m *Model) SetLibrary(library map[string]state.BookInfo) {
+	merged := maps.Clone(library)
+	if merged == nil {
+		merged = make(map[string]state.BookInfo)
+	}
+	maps.Copy(merged, m.library)
+	m.library = merged
+}
+
+// ExportLibrary returns the bookshelf details of the recent files, with
+// the progress of the open book brought up to date, so callers (e.g.
+// main) can persist them. Books that dropped off the recent files list
+// are no longer shown and are left out.
+func (m Model) ExportLibrary() map[string]state.BookInfo {
+	m.library = maps.Clone(m.library)
+	m.updateLibraryProgress()
+	out := make(map[string]state.BookInfo, len(m.recentFiles))
+	for _, path := range m.recentFiles {
+		if info, ok := m.library[path]; ok {
+			out[path] = info
+		}
+	}
+	return out
+}
+
+// addToLibrary records the open book on the bookshelf as just opened.
+// Books without a path, such as those read from standard input, cannot
+// be reopened and are left out.
+func (m *Model) addToLibrary() {
+	if m.currentBook == nil || m.currentBook.SourcePath == "" {
+		return
+	}
+	if m.library == nil {
+		m.library = make(map[string]state.BookInfo)
+	}
+	info := m.library[m.currentBook.SourcePath]
+	info.Title = strings.TrimSpace(m.currentBook.Book.Title)
+	info.Author = strings.TrimSpace(m.currentBook.Book.Author)
+	info.LastOpened = time.Now()
+	m.library[m.currentBook.SourcePath] = info
+	m.updateLibraryProgress()
+}
+
+// updateLibraryProgress stores the reading progress of the open book on
+// the bookshelf.
+func (m *Model) updateLibraryProgress() {
+	if m.currentBook == nil || m.currentBook.SourcePath == "" || m.currentBook.Book.TotalCharacters <= 0 {
+		return
+	}
+	info, ok := m.library[m.currentBook.SourcePath]
+	if !ok {
+		return
+	}
+	abs := min(max(m.positionToAbsoluteOffset(m.currentPos), 0), m.currentBook.Book.TotalCharacters)
+	info.Progress = float64(abs) / float64(m.currentBook.Book.TotalCharacters)
+	m.library[m.currentBook.SourcePath] = info
+}
+
+// shelfBooks returns the recent files as bookshelf rows. Books opened
+// before the bookshelf existed are listed by file name.
+func (m Model) shelfBooks() []render.ShelfBook {
+	books := make([]render.ShelfBook, len(m.recentFiles))
+	for i, path := range m.recentFiles {
+		info, ok := m.library[path]
+		if !ok || info.Title == "" {
+			info.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		books[i] = render.ShelfBook{
+			Title:      info.Title,
+			Author:     info.Author,
+			Progress:   info.Progress,
+			LastOpened: info.LastOpened,
+		}
+	}
+	return books
+}
+
+// renderBookshelf renders the bookshelf shown while no book is open.
+func (m Model) renderBookshelf(height, innerWidth int) []string {
+	header := [4]string{
+		m.tr(i18n.LabelShelfTitle),
+		m.tr(i18n.LabelShelfAuthor),
+		m.tr(i18n.LabelShelfProgress),
+		m.tr(i18n.LabelShelfLastOpened),
+	}
+	top := scrollTopFor(m.shelfTop, m.shelfIndex, height-1)
+	return render.RenderBookshelf(header, m.shelfBooks(), m.shelfIndex, top, height, innerWidth, m.theme.decor())
+}
+
+// handleShelfKey moves through the bookshelf and opens the selected
+// book on Enter.
+func (m *Model) handleShelfKey(msg tea.KeyMsg) bool {
+	if len(m.recentFiles) == 0 {
+		return false
+	}
+	rows := max(1, m.visibleLineCount()-1)
+	switch msg.Type {
+	case tea.KeyUp:
+		m.shelfIndex--
+	case tea.KeyDown:
+		m.shelfIndex++
+	case tea.KeyPgUp:
+		m.shelfIndex -= rows
+	case tea.KeyPgDown:
+		m.shelfIndex += rows
+	case tea.KeyHome:
+		m.shelfIndex = 0
+	case tea.KeyEnd:
+		m.shelfIndex = len(m.recentFiles) - 1
+	case tea.KeyEnter:
+		m.openPath(m.recentFiles[min(max(m.shelfIndex, 0), len(m.recentFiles)-1)])
+		return true
+	default:
+		return false
+	}
+	m.shelfIndex = min(max(m.shelfIndex, 0), len(m.recentFiles)-1)
+	m.shelfTop = scrollTopFor(m.shelfTop, m.shelfIndex, rows)
+	return true
+}
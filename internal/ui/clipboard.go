@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errNoClipboardTool is returned when no clipboard helper program is
+// installed.
+var errNoClipboardTool = errors.New("no clipboard tool found (install wl-clipboard, xclip or xsel)")
+
+// clipboardMsg carries the result of an asynchronous clipboard read.
+type clipboardMsg struct {
+	text string
+	err  error
+}
+
+// readClipboardCmd reads the system clipboard in the background; the
+// result arrives as a clipboardMsg.
+func readClipboardCmd() tea.Cmd {
+	return func() tea.Msg {
+		text, err := readClipboard()
+		return clipboardMsg{text: text, err: err}
+	}
+}
+
+// clipboardWrittenMsg reports the result of an asynchronous clipboard
+// write of n characters.
+type clipboardWrittenMsg struct {
+	n   int
+	err error
+}
+
+// writeClipboardCmd copies text to the system clipboard in the
+// background; the result arrives as a clipboardWrittenMsg.
+func writeClipboardCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		return clipboardWrittenMsg{n: utf8.RuneCountInString(text), err: writeClipboard(text)}
+	}
+}
+
+// clipboardCommands returns the programs that print the clipboard on
+// this platform, in order of preference.
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbpaste"}}
+	case "windows":
+		return [][]string{{"powershell", "-NoProfile", "-Command", "Get-Clipboard"}}
+	}
+	var cmds [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, []string{"wl-paste", "--no-newline"})
+	}
+	return append(cmds,
+		[]string{"xclip", "-selection", "clipboard", "-out"},
+		[]string{"xsel", "--clipboard", "--output"},
+	)
+}
+
+// readClipboard returns the clipboard text using the first available
+// helper program. Reading the clipboard through the terminal (OSC 52)
+// is not used because most terminals disable clipboard reads and
+// Bubble Tea already owns the input stream.
+func readClipboard() (string, error) {
+	for _, args := range clipboardCommands() {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		out, err := exec.Command(args[0], args[1:]...).Output()
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	}
+	return "", errNoClipboardTool
+}
+
+// clipboardWriteCommands returns the programs that set the clipboard
+// from their standard input on this platform, in order of preference.
+func clipboardWriteCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		// clip.exe mangles non-ASCII text; have PowerShell read UTF-8.
+		return [][]string{{"powershell", "-NoProfile", "-Command",
+			"[Console]::InputEncoding = [Text.Encoding]::UTF8; Set-Clipboard -Value ([Console]::In.ReadToEnd())"}}
+	}
+	var cmds [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, []string{"wl-copy"})
+	}
+	return append(cmds,
+		[]string{"xclip", "-selection", "clipboard", "-in"},
+		[]string{"xsel", "--clipboard", "--input"},
+	)
+}
+
+// writeClipboard sets the clipboard to text using the first available
+// helper program.
+func writeClipboard(text string) error {
+	for _, args := range clipboardWriteCommands() {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return errNoClipboardTool
+}
+
+// firstLine returns the first non-blank line of s, trimmed.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// highlightCode colors the parts of the given visual line that fall in
+// a code span of the book, like highlightSearchMatches does for
+// matches. The colors are added as the line is drawn rather than when
+// the text is wrapped, so that escape sequences never count towards the
+// width or offsets of m.lines.
+func (m Model) highlightCode(line string, lineIdx int, indexMap []int) string {
+	if m.currentBook == nil || len(m.currentBook.Spans) == 0 || lineIdx < 0 || lineIdx >= len(m.lineOffsets) {
+		return line
+	}
+	spans := m.currentBook.Spans
+	lineStart := m.lineOffsets[lineIdx]
+	lineLen := utf8.RuneCountInString(m.lines[lineIdx])
+	lineEnd := lineStart + lineLen
+	runes := []rune(line)
+	toDisplay := func(i int) int {
+		if indexMap != nil {
+			i = indexMap[i]
+		}
+		return min(i, len(runes))
+	}
+
+	// Spans do not overlap, so the first one ending after the line start
+	// is the first that can reach into it.
+	first := sort.Search(len(spans), func(i int) bool {
+		return spans[i].Offset+spans[i].Length > lineStart
+	})
+
+	var b strings.Builder
+	pos := 0
+	for _, span := range spans[first:] {
+		if span.Offset >= lineEnd {
+			break
+		}
+		if !span.CodeBlock {
+			continue
+		}
+		from := max(toDisplay(max(span.Offset-lineStart, 0)), pos)
+		to := toDisplay(min(span.Offset+span.Length-lineStart, lineLen))
+		if from >= to {
+			continue
+		}
+		b.WriteString(string(runes[pos:from]))
+		b.WriteString(m.theme.applyCode(string(runes[from:to])))
+		pos = to
+	}
+	b.WriteString(string(runes[pos:]))
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Color schemes reported by detectColorScheme.
+const (
+	colorSchemeDark  = "dark"
+	colorSchemeLight = "light"
+)
+
+// colorSchemeTimeout bounds how long detectColorScheme waits for the
+// terminal to answer; terminals without OSC 11 support never reply.
+const colorSchemeTimeout = 100 * time.Millisecond
+
+// detectColorScheme guesses whether the terminal has a light or dark
+// background. It asks the terminal for its background color with an
+// OSC 11 query and, if no answer arrives in time, falls back to
+// $TERM_PROGRAM hints. It returns "" when nothing is known.
+//
+// The query reads from the terminal directly, so it must run before
+// Bubble Tea starts reading input.
+func detectColorScheme() string {
+	if scheme := queryBackgroundScheme(); scheme != "" {
+		return scheme
+	}
+	// Terminal.app ships with a light default profile.
+	if os.Getenv("TERM_PROGRAM") == "Apple_Terminal" {
+		return colorSchemeLight
+	}
+	return ""
+}
+
+// queryBackgroundScheme sends an OSC 11 query to the controlling
+// terminal and classifies the reply. It returns "" on any failure.
+func queryBackgroundScheme() string {
+	if runtime.GOOS == "windows" || os.Getenv("TERM") == "dumb" {
+		return ""
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return ""
+	}
+	defer tty.Close()
+
+	// Use SyscallConn rather than Fd so the file stays non-blocking and
+	// the read deadline below takes effect.
+	rc, err := tty.SyscallConn()
+	if err != nil {
+		return ""
+	}
+	var state *term.State
+	var rawErr error
+	if err := rc.Control(func(fd uintptr) {
+		state, rawErr = term.MakeRaw(int(fd))
+	}); err != nil || rawErr != nil {
+		return ""
+	}
+	defer rc.Control(func(fd uintptr) {
+		_ = term.Restore(int(fd), state)
+	})
+
+	if _, err := tty.WriteString("\x1b]11;?\x1b\\"); err != nil {
+		return ""
+	}
+	if err := tty.SetReadDeadline(time.Now().Add(colorSchemeTimeout)); err != nil {
+		return ""
+	}
+	var reply []byte
+	buf := make([]byte, 64)
+	for len(reply) < 256 {
+		n, err := tty.Read(buf)
+		reply = append(reply, buf[:n]...)
+		if err != nil {
+			break
+		}
+		// Replies end with ST (ESC \) or BEL.
+		if s := string(reply); strings.HasSuffix(s, "\x1b\\") || strings.HasSuffix(s, "\a") {
+			break
+		}
+	}
+	return schemeFromOSC11(string(reply))
+}
+
+// schemeFromOSC11 classifies an OSC 11 reply such as
+// "\x1b]11;rgb:ffff/ffff/ffff\x1b\\" by the perceived brightness of the
+// color. It returns "" if the reply cannot be parsed.
+func schemeFromOSC11(reply string) string {
+	_, spec, ok := strings.Cut(reply, "rgb:")
+	if !ok {
+		return ""
+	}
+	spec = strings.TrimRight(spec, "\x1b\\\a")
+	parts := strings.Split(spec, "/")
+	if len(parts) != 3 {
+		return ""
+	}
+	var rgb [3]float64
+	for i, p := range parts {
+		if p == "" || len(p) > 4 {
+			return ""
+		}
+		v, err := strconv.ParseUint(p, 16, 16)
+		if err != nil {
+			return ""
+		}
+		// Components have 1-4 hex digits; scale each to 0..1.
+		rgb[i] = float64(v) / float64(uint64(1)<<(4*len(p))-1)
+	}
+	luma := 0.299*rgb[0] + 0.587*rgb[1] + 0.114*rgb[2]
+	if luma > 0.5 {
+		return colorSchemeLight
+	}
+	return colorSchemeDark
+}
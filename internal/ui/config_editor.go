@@ -0,0 +1,237 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+
+	"thujareader/internal/config"
+	"thujareader/internal/i18n"
+)
+
+// configField is one row of the settings dialog: a Config field under
+// its config file name, with conversions from and to the text shown and
+// typed in the dialog. Fields without set are booleans, which Enter
+// toggles instead of prompting.
+type configField struct {
+	name   string
+	get    func(config.Config) string
+	set    func(*config.Config, string) error
+	toggle func(*config.Config)
+}
+
+// configFields lists the editable settings in the order of Config.
+var configFields = []configField{
+	stringField("theme_override", func(c *config.Config) *string { return &c.ThemeOverride }),
+	intField("recent_list_size", func(c *config.Config) *int { return &c.RecentListSize }),
+	stringField("default_library_path", func(c *config.Config) *string { return &c.DefaultLibraryPath }),
+	boolField("tab_completion_enabled", func(c *config.Config) *bool { return &c.TabCompletionEnabled }),
+	intField("input_history_size", func(c *config.Config) *int { return &c.InputHistorySize }),
+	{
+		name: "g_key",
+		get:  func(c config.Config) string { return c.GKey },
+		set: func(c *config.Config, s string) error {
+			if s != config.GKeyGotoPercent && s != config.GKeyEnd {
+				return fmt.Errorf("g_key must be %q or %q", config.GKeyGotoPercent, config.GKeyEnd)
+			}
+			c.GKey = s
+			return nil
+		},
+	},
+	stringField("language", func(c *config.Config) *string { return &c.Language }),
+	boolField("auto_theme", func(c *config.Config) *bool { return &c.AutoTheme }),
+	intField("bookshelf_scan_depth", func(c *config.Config) *int { return &c.BookshelfScanDepth }),
+	boolField("word_wrap", func(c *config.Config) *bool { return &c.WordWrap }),
+	intField("scroll_lines", func(c *config.Config) *int { return &c.ScrollLines }),
+	intField("tab_width", func(c *config.Config) *int { return &c.TabWidth }),
+	intField("auto_scroll_delay", func(c *config.Config) *int { return &c.AutoScrollDelay }),
+	boolField("allow_delete_toc", func(c *config.Config) *bool { return &c.AllowDeleteTOC }),
+	intField("left_margin", func(c *config.Config) *int { return &c.LeftMargin }),
+	intField("right_margin", func(c *config.Config) *int { return &c.RightMargin }),
+	intField("reading_goals.daily_chars_target", func(c *config.Config) *int { return &c.ReadingGoals.DailyCharsTarget }),
+	{
+		name: "keybindings",
+		get:  func(c config.Config) string { return formatKeybindings(c.Keybindings) },
+		set: func(c *config.Config, s string) error {
+			bindings, err := parseKeybindings(s)
+			if err == nil {
+				c.Keybindings = bindings
+			}
+			return err
+		},
+	},
+}
+
+// stringField returns a configField for a free-form string setting.
+func stringField(name string, field func(*config.Config) *string) configField {
+	return configField{
+		name: name,
+		get:  func(c config.Config) string { return *field(&c) },
+		set: func(c *config.Config, s string) error {
+			*field(c) = s
+			return nil
+		},
+	}
+}
+
+// intField returns a configField for a whole-number setting.
+func intField(name string, field func(*config.Config) *int) configField {
+	return configField{
+		name: name,
+		get:  func(c config.Config) string { return strconv.Itoa(*field(&c)) },
+		set: func(c *config.Config, s string) error {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return fmt.Errorf("%s must be a whole number", name)
+			}
+			*field(c) = n
+			return nil
+		},
+	}
+}
+
+// boolField returns a configField for an on/off setting.
+func boolField(name string, field func(*config.Config) *bool) configField {
+	return configField{
+		name:   name,
+		get:    func(c config.Config) string { return strconv.FormatBool(*field(&c)) },
+		toggle: func(c *config.Config) { *field(c) = !*field(c) },
+	}
+}
+
+// formatKeybindings shows key overrides as "name=key" pairs sorted by
+// command name, e.g. "find=ctrl+f, toc=t".
+func formatKeybindings(bindings map[string]string) string {
+	pairs := make([]string, 0, len(bindings))
+	for _, name := range slices.Sorted(maps.Keys(bindings)) {
+		pairs = append(pairs, name+"="+bindings[name])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// parseKeybindings parses the format of formatKeybindings. Empty input
+// removes all overrides.
+func parseKeybindings(s string) (map[string]string, error) {
+	var bindings map[string]string
+	for pair := range strings.SplitSeq(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, key, ok := strings.Cut(pair, "=")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !ok || name == "" || key == "" {
+			return nil, fmt.Errorf("keybindings must look like find=ctrl+f, toc=t")
+		}
+		if bindings == nil {
+			bindings = make(map[string]string)
+		}
+		bindings[name] = key
+	}
+	return bindings, nil
+}
+
+// SetConfigPath tells the model where the settings dialog saves the
+// configuration.
+func (m *Model) SetConfigPath(path string) {
+	m.configPath = path
+}
+
+// openConfigEditor opens the settings dialog on a copy of the current
+// configuration.
+func (m *Model) openConfigEditor() {
+	m.configDraft = m.config
+	m.configDraft.Keybindings = maps.Clone(m.config.Keybindings)
+	m.configOpen = true
+	m.configIndex = 0
+	m.configTop = 0
+	m.setStatus(m.tr(i18n.MsgConfigHint))
+}
+
+// configLabels returns the rows of the settings dialog: every field
+// with its value, a changed one marked with "*", followed by the Save
+// action.
+func (m Model) configLabels() []string {
+	nameWidth := 0
+	for _, f := range configFields {
+		nameWidth = max(nameWidth, runewidth.StringWidth(f.name))
+	}
+	labels := make([]string, 0, len(configFields)+1)
+	for _, f := range configFields {
+		mark := " "
+		if f.get(m.configDraft) != f.get(m.config) {
+			mark = "*"
+		}
+		labels = append(labels, mark+runewidth.FillRight(f.name, nameWidth)+"  "+f.get(m.configDraft))
+	}
+	return append(labels, m.tr(i18n.LabelConfigSave))
+}
+
+// handleConfigKey handles keys in the settings dialog: ↑/↓ select a
+// field, Enter edits it (or toggles an on/off setting, or saves on the
+// Save row), S saves and Esc closes without saving.
+func (m *Model) handleConfigKey(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.configOpen = false
+		m.setStatus(m.tr(i18n.MsgCancelled))
+		return true
+	case tea.KeyUp:
+		m.configIndex--
+	case tea.KeyDown:
+		m.configIndex++
+	case tea.KeyEnter:
+		if m.configIndex >= len(configFields) {
+			m.saveConfig()
+			return true
+		}
+		f := configFields[m.configIndex]
+		if f.toggle != nil {
+			f.toggle(&m.configDraft)
+			return true
+		}
+		m.startInput(cmdConfig, m.tr(i18n.PromptConfigValue, f.name))
+		m.setInput(f.get(m.configDraft))
+		return true
+	case tea.KeyRunes:
+		if len(msg.Runes) == 1 && (msg.Runes[0] == 's' || msg.Runes[0] == 'S') {
+			m.saveConfig()
+		}
+		return true
+	default:
+		return true
+	}
+	m.configIndex = min(max(m.configIndex, 0), len(configFields))
+	m.configTop = scrollTopFor(m.configTop, m.configIndex, m.dialogListRows())
+	return true
+}
+
+// setConfigValue applies input typed for the selected field to the
+// draft, keeping the old value if the input is invalid.
+func (m *Model) setConfigValue(input string) {
+	if !m.configOpen || m.configIndex < 0 || m.configIndex >= len(configFields) {
+		return
+	}
+	if err := configFields[m.configIndex].set(&m.configDraft, input); err != nil {
+		m.setStatus(m.tr(i18n.MsgConfigInvalid, err))
+		return
+	}
+	m.setStatus(m.tr(i18n.MsgConfigHint))
+}
+
+// saveConfig writes the draft to the config file and applies it. The
+// dialog stays open if the file cannot be written.
+func (m *Model) saveConfig() {
+	if err := config.Save(m.configPath, m.configDraft); err != nil {
+		m.setStatus(m.tr(i18n.MsgConfigSaveFailed, err))
+		return
+	}
+	m.configOpen = false
+	m.applyConfig(m.configDraft)
+	m.reflowWrappedLines()
+	m.setStatus(m.tr(i18n.MsgConfigSaved, m.configPath))
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+
+	"thujareader/internal/i18n"
+	"thujareader/internal/reader"
+)
+
+// SetAcceptDrops makes the model open files dropped onto the terminal
+// window. Terminals deliver a drop as a bracketed paste of the file's
+// path, which would otherwise be ignored while reading.
+func (m *Model) SetAcceptDrops(accept bool) {
+	m.acceptDrops = accept
+	if accept && m.currentBook == nil {
+		m.setStatus(m.tr(i18n.MsgDropHint))
+	}
+}
+
+// droppedPath extracts the path of a dropped file from pasted text. It
+// accepts the forms terminals use: a plain or quoted path, one with
+// backslash-escaped spaces, or a file:// URI. Of several dropped files
+// only the first is used. URLs are passed through for openPath to
+// download. ok is false when the text does not name an existing file.
+func droppedPath(text string) (path string, ok bool) {
+	path = firstLine(text)
+	if reader.IsURL(path) {
+		return path, true
+	}
+	if strings.HasPrefix(path, "file://") {
+		u, err := url.Parse(path)
+		if err != nil {
+			return "", false
+		}
+		path = u.Path
+	} else if len(path) >= 2 && (path[0] == '\'' || path[0] == '"') && path[len(path)-1] == path[0] {
+		path = path[1 : len(path)-1]
+	} else if !isFile(path) && runtime.GOOS != "windows" {
+		path = unescapeShellPath(path)
+	}
+	if !isFile(path) {
+		return "", false
+	}
+	return path, true
+}
+
+// isFile reports whether path names an existing file that is not a
+// directory.
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// unescapeShellPath removes the backslashes that some terminals put
+// before spaces and other special characters of a dropped path.
+func unescapeShellPath(path string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range path {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"thujareader/internal/reader"
+)
+
+// FileBrowserModel is the state of the Open dialog: a directory listing
+// with the parent directory, the subdirectories, and the books found in
+// the directory (and, depending on the scan depth, below it).
+type FileBrowserModel struct {
+	dir      string
+	entries  []fileBrowserEntry
+	selected int
+	top      int
+
+	// depth and extensions are passed to reader.ScanDirectory when
+	// listing books.
+	depth      int
+	extensions []string
+}
+
+// fileBrowserEntry is one row of the file browser.
+type fileBrowserEntry struct {
+	label string // name relative to the browsed directory
+	path  string
+	isDir bool
+}
+
+// newFileBrowser returns an empty browser that lists books with the
+// given extensions up to depth directory levels deep; call chdir to
+// fill it.
+func newFileBrowser(depth int, extensions []string) FileBrowserModel {
+	return FileBrowserModel{depth: depth, extensions: extensions}
+}
+
+// chdir lists dir and selects its first entry. On error the browser is
+// left unchanged.
+func (b *FileBrowserModel) chdir(dir string) error {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	books, err := reader.ScanDirectory(dir, b.depth, b.extensions)
+	if err != nil {
+		return err
+	}
+
+	var entries []fileBrowserEntry
+	if parent := filepath.Dir(dir); parent != dir {
+		entries = append(entries, fileBrowserEntry{label: "..", path: parent, isDir: true})
+	}
+	for _, e := range dirEntries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		// Follow symlinks so linked library folders can be browsed.
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			continue
+		}
+		entries = append(entries, fileBrowserEntry{
+			label: e.Name() + string(filepath.Separator),
+			path:  path,
+			isDir: true,
+		})
+	}
+	for _, path := range books {
+		label, err := filepath.Rel(dir, path)
+		if err != nil {
+			label = filepath.Base(path)
+		}
+		entries = append(entries, fileBrowserEntry{label: label, path: path})
+	}
+
+	b.dir = dir
+	b.entries = entries
+	b.selected = 0
+	b.top = 0
+	return nil
+}
+
+// up moves to the parent directory and selects the directory that was
+// just left, so that going down and back up keeps the user's place.
+func (b *FileBrowserModel) up() error {
+	parent := filepath.Dir(b.dir)
+	if parent == b.dir {
+		return nil
+	}
+	left := b.dir
+	if err := b.chdir(parent); err != nil {
+		return err
+	}
+	for i, e := range b.entries {
+		if e.isDir && e.path == left {
+			b.selected = i
+			break
+		}
+	}
+	return nil
+}
+
+// move changes the selection by delta entries, clamped to the list,
+// and keeps it visible in a viewport of rows entries.
+func (b *FileBrowserModel) move(delta, rows int) {
+	b.selected = min(max(b.selected+delta, 0), max(0, len(b.entries)-1))
+	b.top = scrollTopFor(b.top, b.selected, rows)
+}
+
+// current returns the selected entry, if any.
+func (b FileBrowserModel) current() (fileBrowserEntry, bool) {
+	if b.selected < 0 || b.selected >= len(b.entries) {
+		return fileBrowserEntry{}, false
+	}
+	return b.entries[b.selected], true
+}
+
+// labels returns the display label of every entry.
+func (b FileBrowserModel) labels() []string {
+	out := make([]string, len(b.entries))
+	for i, e := range b.entries {
+		out[i] = e.label
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"thujareader/internal/i18n"
+	"thujareader/internal/reader"
+)
+
+// footnoteAt returns the footnote marker covering the rune offset.
+func (m Model) footnoteAt(offset int) (reader.FootnoteRef, bool) {
+	if m.currentBook == nil {
+		return reader.FootnoteRef{}, false
+	}
+	for _, ref := range m.currentBook.FootnoteRefs {
+		if offset >= ref.Offset && offset < ref.Offset+ref.Length {
+			return ref, true
+		}
+	}
+	return reader.FootnoteRef{}, false
+}
+
+// firstVisibleFootnote returns the first footnote marker on screen.
+func (m Model) firstVisibleFootnote() (reader.FootnoteRef, bool) {
+	if m.currentBook == nil || len(m.lineOffsets) == 0 {
+		return reader.FootnoteRef{}, false
+	}
+	from := m.lineOffsets[min(max(m.topLine, 0), len(m.lineOffsets)-1)]
+	to := len(m.textRunes)
+	if end := m.topLine + max(1, m.visibleLineCount()); end < len(m.lineOffsets) {
+		to = m.lineOffsets[end]
+	}
+	for _, ref := range m.currentBook.FootnoteRefs {
+		if ref.Offset >= from && ref.Offset < to {
+			return ref, true
+		}
+	}
+	return reader.FootnoteRef{}, false
+}
+
+// showFootnote shows the footnote whose marker is under the cursor in
+// a popup. Outside cursor mode it enters the mode first, with the
+// cursor on the first marker on screen, so the note can be picked.
+func (m *Model) showFootnote() {
+	if m.currentBook == nil {
+		m.setStatus(m.tr(i18n.MsgFootnoteNoBook))
+		return
+	}
+	if len(m.currentBook.FootnoteRefs) == 0 {
+		m.setStatus(m.tr(i18n.MsgFootnotesNone))
+		return
+	}
+	if !m.cursorMode {
+		m.startCursor()
+		if ref, ok := m.firstVisibleFootnote(); ok {
+			m.moveCursorTo(ref.Offset)
+		}
+		m.setStatus(m.tr(i18n.MsgFootnotePick))
+		return
+	}
+	ref, ok := m.footnoteAt(m.cursorOffset())
+	if !ok {
+		m.setStatus(m.tr(i18n.MsgFootnoteNotMarker))
+		return
+	}
+	text, ok := m.currentBook.Footnotes[ref.ID]
+	if !ok {
+		m.setStatus(m.tr(i18n.MsgFootnoteMissing, ref.ID))
+		return
+	}
+	marker := string(m.textRunes[ref.Offset:min(ref.Offset+ref.Length, len(m.textRunes))])
+	m.showPopup(m.tr(i18n.LabelFootnoteTitle, marker), text)
+}
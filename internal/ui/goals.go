@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"maps"
+	"time"
+
+	"thujareader/internal/i18n"
+)
+
+// SetDailyProgress installs the persisted characters read per day,
+// keyed by local date as "2006-01-02".
+func (m *Model) SetDailyProgress(progress map[string]int) {
+	m.dailyProgress = maps.Clone(progress)
+	if m.dailyProgress == nil {
+		m.dailyProgress = make(map[string]int)
+	}
+}
+
+// ExportDailyProgress returns a copy of the characters read per day so
+// that callers (e.g. main) can persist them.
+func (m Model) ExportDailyProgress() map[string]int {
+	return maps.Clone(m.dailyProgress)
+}
+
+// today returns the key of the current day in dailyProgress.
+func today() string {
+	return time.Now().Format(time.DateOnly)
+}
+
+// countScrolledText adds the text scrolled past since the viewport's
+// top line was fromLine to today's progress, and announces when that
+// reaches the daily goal. Jumps are not counted; only scrolling calls
+// it.
+func (m *Model) countScrolledText(fromLine int) {
+	if m.topLine <= fromLine || fromLine < 0 || m.topLine >= len(m.lineOffsets) {
+		return
+	}
+	if m.dailyProgress == nil {
+		m.dailyProgress = make(map[string]int)
+	}
+	day := today()
+	before := m.dailyProgress[day]
+	after := before + m.lineOffsets[m.topLine] - m.lineOffsets[fromLine]
+	m.dailyProgress[day] = after
+	if target := m.config.ReadingGoals.DailyCharsTarget; target > 0 && before < target && after >= target {
+		m.setTransientStatus(m.tr(i18n.MsgDailyGoalReached, target))
+	}
+}
+
+// dailyGoalLabel returns the status bar's "Today: read / target"
+// indicator, or "" when no daily goal is set.
+func (m Model) dailyGoalLabel() string {
+	target := m.config.ReadingGoals.DailyCharsTarget
+	if target <= 0 {
+		return ""
+	}
+	return m.tr(i18n.LabelDailyGoal, m.dailyProgress[today()], target)
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+
+	"thujareader/internal/i18n"
+)
+
+// readingKeys lists the keys that are not bound to menu commands, for
+// the help screen, with their i18n descriptions.
+var readingKeys = []struct{ key, desc string }{
+	{"↑/↓", i18n.HelpScrollLine},
+	{"PgUp/PgDn", i18n.HelpScrollPage},
+	{"Ctrl+U/Ctrl+D", i18n.HelpScrollHalfPage},
+	{"Home/End", i18n.HelpStartEnd},
+	{"Enter", i18n.HelpCursor},
+	{"F7", i18n.HelpFindNext},
+	{"F10", i18n.HelpMenuBar},
+	{"Alt+F, Alt+S…", i18n.HelpAltMenu},
+	{"Esc", i18n.HelpClose},
+}
+
+// helpLines returns the lines of the help screen: every menu command
+// that has a key, under its menu, followed by the reading keys. It is
+// derived from the menus and the active bindings so it cannot go stale.
+func (m Model) helpLines() []string {
+	type entry struct{ key, desc string }
+	var sections [][]entry
+	var titles []string
+	keyWidth := 0
+	for _, mn := range m.menus {
+		var entries []entry
+		for _, item := range mn.items {
+			if key := keyHint(m.keyBindings[item.command]); key != "" {
+				entries = append(entries, entry{key, item.label})
+				keyWidth = max(keyWidth, runewidth.StringWidth(key))
+			}
+		}
+		if len(entries) > 0 {
+			titles = append(titles, mn.label)
+			sections = append(sections, entries)
+		}
+	}
+	var reading []entry
+	for _, k := range readingKeys {
+		reading = append(reading, entry{k.key, m.tr(k.desc)})
+		keyWidth = max(keyWidth, runewidth.StringWidth(k.key))
+	}
+	titles = append(titles, m.tr(i18n.LabelHelpReading))
+	sections = append(sections, reading)
+
+	var lines []string
+	for i, entries := range sections {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, " "+titles[i])
+		for _, e := range entries {
+			pad := strings.Repeat(" ", keyWidth-runewidth.StringWidth(e.key))
+			lines = append(lines, "   "+e.key+pad+"  "+e.desc)
+		}
+	}
+	return lines
+}
+
+// handleHelpKey scrolls the help screen; Esc closes it (F1 is handled
+// globally). Other keys are swallowed while the screen is open.
+func (m *Model) handleHelpKey(msg tea.KeyMsg) bool {
+	page := max(1, m.visibleLineCount())
+	maxTop := max(0, len(m.helpLines())-page)
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.helpOpen = false
+	case tea.KeyUp:
+		m.helpTopLine--
+	case tea.KeyDown:
+		m.helpTopLine++
+	case tea.KeyPgUp:
+		m.helpTopLine -= page
+	case tea.KeyPgDown:
+		m.helpTopLine += page
+	case tea.KeyHome:
+		m.helpTopLine = 0
+	case tea.KeyEnd:
+		m.helpTopLine = maxTop
+	}
+	m.helpTopLine = min(max(m.helpTopLine, 0), maxTop)
+	return true
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultKeyBindings maps commands to the key that triggers them
+// directly, in Bubble Tea's KeyMsg.String form (e.g. "f3", "ctrl+c").
+// Menu hints are derived from the active bindings at render time so
+// they cannot go stale when a binding changes.
+var defaultKeyBindings = map[commandID]string{
+	cmdOpen:        "f3",
+	cmdExit:        "ctrl+c",
+	cmdFind:        "f7",
+	cmdAddBookmark: "f2",
+	cmdHelp:        "f1",
+	cmdGotoPercent: "G",
+	cmdGotoChapter: "c", // Ctrl+G works as well
+	cmdGotoLine:    ":",
+	cmdOpenURL:     "ctrl+v",
+	cmdReloadBook:  "f5",
+	// Terminals do not report Ctrl+Tab, so tabs cycle with
+	// Ctrl+PgDn/PgUp as in most browsers.
+	cmdNewTab:   "ctrl+t",
+	cmdCloseTab: "ctrl+w",
+	cmdNextTab:  "ctrl+pgdown",
+	cmdPrevTab:  "ctrl+pgup",
+
+	// Ctrl+W already closes the tab.
+	cmdSplitView: "ctrl+b",
+
+	cmdSelectText: "v",
+	cmdAddNote:    "n",
+	cmdLookup:     "d",
+	cmdAutoScroll: "a",
+	cmdFootnote:   "f",
+	cmdMetadata:   "i",
+	cmdZoomIn:     "+",
+	cmdZoomOut:    "-",
+	cmdZoomReset:  "0",
+
+	// Terminals report Shift+F7 as F19, which handleKey maps.
+	cmdFindPrevious: "shift+f7",
+}
+
+// commandNames maps the command names used by the keybindings setting
+// in config.json to commands.
+var commandNames = map[string]commandID{
+	"open":                     cmdOpen,
+	"exit":                     cmdExit,
+	"find":                     cmdFind,
+	"find_previous":            cmdFindPrevious,
+	"toc":                      cmdToc,
+	"bookmarks":                cmdBookmarks,
+	"recent_files":             cmdRecentFiles,
+	"help":                     cmdHelp,
+	"add_bookmark":             cmdAddBookmark,
+	"delete_bookmark":          cmdDeleteBookmark,
+	"toggle_search_whitespace": cmdToggleSearchWhitespace,
+	"clear_recent_files":       cmdClearRecentFiles,
+	"goto_percent":             cmdGotoPercent,
+	"goto_chapter":             cmdGotoChapter,
+	"goto_line":                cmdGotoLine,
+	"book_info":                cmdMetadata,
+	"open_url":                 cmdOpenURL,
+	"reload":                   cmdReloadBook,
+	"search_mode":              cmdSearchMode,
+	"word_wrap":                cmdToggleWordWrap,
+	"rename_bookmark":          cmdRenameBookmark,
+	"new_tab":                  cmdNewTab,
+	"close_tab":                cmdCloseTab,
+	"next_tab":                 cmdNextTab,
+	"prev_tab":                 cmdPrevTab,
+	"select_text":              cmdSelectText,
+	"zoom_in":                  cmdZoomIn,
+	"zoom_out":                 cmdZoomOut,
+	"zoom_reset":               cmdZoomReset,
+	"export_bookmarks":         cmdExportBookmarks,
+	"add_note":                 cmdAddNote,
+	"notes":                    cmdNotes,
+	"stats":                    cmdStats,
+	"lookup":                   cmdLookup,
+	"auto_scroll":              cmdAutoScroll,
+	"settings":                 cmdConfig,
+	"split_view":               cmdSplitView,
+	"footnote":                 cmdFootnote,
+	"export_text":              cmdExportText,
+}
+
+// keyBindingsWithOverrides returns the default bindings with the
+// overrides from config.json applied. Overrides map command names to
+// keys; a key given to one command is taken away from any other. They
+// are applied in order of command name, so when two name the same key,
+// the command that sorts last gets it every time. Unknown command names
+// and empty keys are ignored. With gKeyJumpsToEnd, "G" jumps to the end
+// of the book, so Goto Percent loses its default key.
+func keyBindingsWithOverrides(overrides map[string]string, gKeyJumpsToEnd bool) map[commandID]string {
+	out := copyKeyBindings(defaultKeyBindings)
+	if gKeyJumpsToEnd {
+		delete(out, cmdGotoPercent)
+	}
+	for _, name := range slices.Sorted(maps.Keys(overrides)) {
+		key := overrides[name]
+		cmd, ok := commandNames[strings.ToLower(strings.TrimSpace(name))]
+		key = normalizeKey(key)
+		if !ok || key == "" {
+			continue
+		}
+		for other, k := range out {
+			if k == key {
+				delete(out, other)
+			}
+		}
+		out[cmd] = key
+	}
+	return out
+}
+
+// normalizeKey converts a key as a user may write it, e.g. "Ctrl+F",
+// to KeyMsg.String form. Single characters keep their case so that "G"
+// and "g" stay distinct.
+func normalizeKey(key string) string {
+	key = strings.TrimSpace(key)
+	if len([]rune(key)) > 1 {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
+// overriddenCommand returns the command a key was bound to by an
+// override. Keys that kept their default binding are dispatched by
+// handleKey's own cases, which carry their special behaviour (such as
+// F7 repeating the last search).
+func (m Model) overriddenCommand(msg tea.KeyMsg) (commandID, bool) {
+	key := msg.String()
+	for cmd, k := range m.keyBindings {
+		if k == key && defaultKeyBindings[cmd] != key {
+			return cmd, true
+		}
+	}
+	return cmdNone, false
+}
+
+// copyKeyBindings returns an independent copy of a binding map.
+func copyKeyBindings(src map[commandID]string) map[commandID]string {
+	out := make(map[commandID]string, len(src))
+	for cmd, key := range src {
+		out[cmd] = key
+	}
+	return out
+}
+
+// keyHint formats a key in KeyMsg.String form for display, e.g.
+// "ctrl+shift+f7" becomes "Ctrl+Shift+F7". Single characters are kept
+// as-is so that "G" and "g" stay distinct.
+func keyHint(key string) string {
+	if key == "" {
+		return ""
+	}
+	parts := strings.Split(key, "+")
+	for i, p := range parts {
+		if len([]rune(p)) > 1 {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "+")
+}
@@ -0,0 +1,32 @@
+package ui
+
+import "testing"
+
+func TestKeyBindingOverridesSameKey(t *testing.T) {
+	overrides := map[string]string{"find": "x", "goto_line": "x", "toc": "x"}
+	// Map order varies between runs; the outcome must not.
+	for range 20 {
+		bindings := keyBindingsWithOverrides(overrides, false)
+		if got := bindings[cmdToc]; got != "x" {
+			t.Fatalf("toc bound to %q, want x", got)
+		}
+		for _, cmd := range []commandID{cmdFind, cmdGotoLine} {
+			if key, ok := bindings[cmd]; ok {
+				t.Fatalf("command %d kept key %q", cmd, key)
+			}
+		}
+	}
+}
+
+func TestGKeyEndDropsGotoPercentBinding(t *testing.T) {
+	if key := keyBindingsWithOverrides(nil, false)[cmdGotoPercent]; key != "G" {
+		t.Errorf("goto percent bound to %q, want G", key)
+	}
+	if key, ok := keyBindingsWithOverrides(nil, true)[cmdGotoPercent]; ok {
+		t.Errorf("with g_key=end, goto percent still bound to %q", key)
+	}
+	bindings := keyBindingsWithOverrides(map[string]string{"goto_percent": "%"}, true)
+	if key := bindings[cmdGotoPercent]; key != "%" {
+		t.Errorf("goto percent override gave %q, want %%", key)
+	}
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// canBreakBefore reports whether a line may be broken between prev and
+// r, where prev2 precedes prev. It follows the rules of the Unicode line
+// breaking algorithm (UAX #14) that matter for book text: a break may
+// come before and after CJK ideographs, kana and Hangul, after a hyphen
+// inside a word, and between Thai syllables, but never before a
+// combining mark or closing punctuation, nor after opening punctuation
+// or a no-break space. Breaks at spaces are left to the caller.
+func canBreakBefore(prev2, prev, r rune) bool {
+	switch {
+	case r == ' ' || prev == ' ' || prev == '\t' || prev == '\n':
+		return false
+	case isCombining(r) || isGlue(r) || isGlue(prev):
+		return false
+	case isNoBreakStart(r) || isNoBreakEnd(prev):
+		return false
+	case isHyphen(prev):
+		// "well-known" may break after the hyphen, but not "-5" or
+		// "--".
+		return unicode.IsLetter(prev2) && unicode.IsLetter(r)
+	case prev == '\u2014' || r == '\u2014':
+		return true
+	case isIdeographic(prev) || isIdeographic(r):
+		return true
+	case isThai(prev) && isThai(r):
+		return thaiSyllableBreak(prev, r)
+	}
+	return false
+}
+
+// isCombining reports whether r attaches to the rune before it, so that
+// a line cannot start with it.
+func isCombining(r rune) bool {
+	return !norm.NFC.PropertiesString(string(r)).BoundaryBefore() ||
+		unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me) ||
+		r == '\u200d' || unicode.Is(unicode.Variation_Selector, r)
+}
+
+// isGlue reports whether r forbids a break on either side of it (UAX #14
+// classes GL and WJ).
+func isGlue(r rune) bool {
+	switch r {
+	case '\u00a0', '\u202f', '\u2007', '\u2060', '\ufeff', '\u200d':
+		return true
+	}
+	return false
+}
+
+// isHyphen reports whether a break may follow r inside a word (classes
+// HY and BA).
+func isHyphen(r rune) bool {
+	switch r {
+	case '-', '\u00ad', '\u2010', '\u2013':
+		return true
+	}
+	return false
+}
+
+// isNoBreakStart reports whether r must not start a line: closing
+// brackets and quotes, and punctuation that ends a phrase, including
+// the small kana and iteration marks of Japanese (classes CL, CP, EX,
+// IS and NS).
+func isNoBreakStart(r rune) bool {
+	if unicode.In(r, unicode.Pe, unicode.Pf) {
+		return true
+	}
+	switch r {
+	case '!', '?', ',', '.', ':', ';', '/', '…', '‥',
+		'、', '。', '，', '．', '！', '？', '：', '；', '・', '〜', 'ー',
+		'々', '〻', 'ゝ', 'ゞ', 'ヽ', 'ヾ', '゛', '゜',
+		'ぁ', 'ぃ', 'ぅ', 'ぇ', 'ぉ', 'っ', 'ゃ', 'ゅ', 'ょ', 'ゎ', 'ゕ', 'ゖ',
+		'ァ', 'ィ', 'ゥ', 'ェ', 'ォ', 'ッ', 'ャ', 'ュ', 'ョ', 'ヮ', 'ヵ', 'ヶ',
+		'ｧ', 'ｨ', 'ｩ', 'ｪ', 'ｫ', 'ｬ', 'ｭ', 'ｮ', 'ｯ', 'ｰ', '｡', '､':
+		return true
+	}
+	return false
+}
+
+// isNoBreakEnd reports whether r must not end a line: opening brackets
+// and quotes (class OP).
+func isNoBreakEnd(r rune) bool {
+	return unicode.In(r, unicode.Ps, unicode.Pi)
+}
+
+// isIdeographic reports whether r is a CJK character a line may break
+// on either side of (class ID and its relatives H2, H3).
+func isIdeographic(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303f) || // CJK symbols and punctuation
+		(r >= 0xff01 && r <= 0xff60) // fullwidth forms
+}
+
+// isThai reports whether r is a Thai letter, vowel or mark.
+func isThai(r rune) bool {
+	return r >= 0x0e01 && r <= 0x0e5b
+}
+
+// thaiSyllableBreak approximates the Thai syllable boundaries UAX #14
+// leaves to a dictionary: Thai has no spaces between words, so without
+// one a break is allowed before a leading vowel (เ แ โ ใ ไ), which always
+// starts a syllable, and after a vowel or sign that always ends one
+// (ะ า ำ ๅ ๆ ฯ), unless a following vowel or mark belongs to it.
+func thaiSyllableBreak(prev, r rune) bool {
+	if isCombining(r) || isThaiFollowingVowel(r) || (prev >= 0x0e40 && prev <= 0x0e44) {
+		return false
+	}
+	return (r >= 0x0e40 && r <= 0x0e44) || isThaiFollowingVowel(prev)
+}
+
+// isThaiFollowingVowel reports whether r is a Thai vowel or sign
+// written after the syllable it ends.
+func isThaiFollowingVowel(r rune) bool {
+	switch r {
+	case 'ะ', 'า', 'ำ', 'ๅ', 'ๆ', 'ฯ':
+		return true
+	}
+	return false
+}
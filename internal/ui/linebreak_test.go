@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWrapTextMixedScripts(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{"ideographs", "日本語のテキストです", 6, []string{"日本語", "のテキ", "ストで", "す"}},
+		{"no break before a full stop", "漢字漢字。漢字", 8, []string{"漢字漢", "字。漢字"}},
+		{"no break after an opening bracket", "日本語「本」", 8, []string{"日本語", "「本」"}},
+		{"hyphenated word", "a well-known fact", 7, []string{"a well-", "known", "fact"}},
+		{"no break after a minus sign", "x -5 and --", 3, []string{"x", "-5", "and", "--"}},
+		{"Thai syllables", "ไปโรงเรียน", 4, []string{"ไป", "โรง", "เรียน"}},
+		{"Thai marks take no cells", "ที่นี่", 2, []string{"ที่นี่"}},
+		{
+			"mixed", "Read 日本語 text, a well-known ไปโรงเรียน (example)", 10,
+			[]string{"Read 日本", "語 text, a", "well-known", "ไปโรงเรียน", "(example)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := []rune(tt.text)
+			lines, offsets := wrapText(text, tt.width, true, 4)
+			if !slices.Equal(lines, tt.want) {
+				t.Errorf("wrapText(%q, %d) = %q, want %q", tt.text, tt.width, lines, tt.want)
+			}
+			for i, line := range lines {
+				if got := string(text[offsets[i]:][:len([]rune(line))]); got != line {
+					t.Errorf("line %d starts at offset %d, where the text is %q, not %q", i, offsets[i], got, line)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"thujareader/internal/i18n"
+)
+
+// dictionaryURL is the Free Dictionary API endpoint; the word is
+// appended to it.
+const dictionaryURL = "https://api.dictionaryapi.dev/api/v2/entries/en/"
+
+// lookupClient fetches definitions. A lookup is a small request, so it
+// gives up much sooner than a book download.
+var lookupClient = &http.Client{Timeout: 10 * time.Second}
+
+// errNoDefinition is returned when the dictionary does not know a word.
+var errNoDefinition = errors.New("no definition found")
+
+// lookupMsg carries the result of an asynchronous dictionary lookup.
+type lookupMsg struct {
+	word         string
+	partOfSpeech string
+	definition   string
+	err          error
+}
+
+// lookupCmd looks word up in the background; the result arrives as a
+// lookupMsg.
+func lookupCmd(word string) tea.Cmd {
+	return func() tea.Msg {
+		partOfSpeech, definition, err := lookupWord(word)
+		return lookupMsg{word: word, partOfSpeech: partOfSpeech, definition: definition, err: err}
+	}
+}
+
+// dictionaryEntry is the part of a Free Dictionary API entry that is
+// shown.
+type dictionaryEntry struct {
+	Meanings []struct {
+		PartOfSpeech string `json:"partOfSpeech"`
+		Definitions  []struct {
+			Definition string `json:"definition"`
+		} `json:"definitions"`
+	} `json:"meanings"`
+}
+
+// lookupWord returns the first definition of word in the dictionary,
+// with its part of speech.
+func lookupWord(word string) (partOfSpeech, definition string, err error) {
+	resp, err := lookupClient.Get(dictionaryURL + url.PathEscape(strings.ToLower(word)))
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", "", errNoDefinition
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", "", fmt.Errorf("dictionary: %s", resp.Status)
+	}
+	var entries []dictionaryEntry
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&entries); err != nil {
+		return "", "", fmt.Errorf("dictionary: %w", err)
+	}
+	for _, entry := range entries {
+		for _, meaning := range entry.Meanings {
+			for _, d := range meaning.Definitions {
+				if text := strings.TrimSpace(d.Definition); text != "" {
+					return meaning.PartOfSpeech, text, nil
+				}
+			}
+		}
+	}
+	return "", "", errNoDefinition
+}
+
+// startCursor enters cursor mode with the cursor at the start of the
+// first visible line.
+func (m *Model) startCursor() {
+	if m.currentBook == nil || len(m.lineOffsets) == 0 {
+		return
+	}
+	m.cursorMode = true
+	m.cursorLine = min(max(m.topLine, 0), len(m.lineOffsets)-1)
+	m.cursorCol = 0
+	m.setStatus(m.tr(i18n.MsgCursorHint))
+}
+
+// handleCursorKey handles keys in cursor mode: the arrows, PgUp/PgDn
+// and Home/End move the cursor, and Esc or Enter leave the mode. Other
+// keys, such as d for cmdLookup, fall through to the reading keys.
+func (m *Model) handleCursorKey(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyEnter:
+		m.cursorMode = false
+		m.setStatus(m.tr(i18n.MsgCursorOff))
+	case tea.KeyLeft:
+		m.moveCursorTo(m.cursorOffset() - 1)
+	case tea.KeyRight:
+		m.moveCursorTo(m.cursorOffset() + 1)
+	case tea.KeyUp:
+		m.moveCursorLines(-1)
+	case tea.KeyDown:
+		m.moveCursorLines(1)
+	case tea.KeyPgUp:
+		m.moveCursorLines(-max(1, m.visibleLineCount()))
+	case tea.KeyPgDown:
+		m.moveCursorLines(max(1, m.visibleLineCount()))
+	case tea.KeyHome:
+		m.cursorCol = 0
+	case tea.KeyEnd:
+		m.cursorCol = max(0, utf8.RuneCountInString(m.lines[m.cursorLine])-1)
+	default:
+		return false
+	}
+	return true
+}
+
+// cursorOffset returns the rune offset into textRunes under the cursor.
+func (m Model) cursorOffset() int {
+	if m.cursorLine < 0 || m.cursorLine >= len(m.lineOffsets) {
+		return 0
+	}
+	return m.lineOffsets[m.cursorLine] + m.cursorCol
+}
+
+// moveCursorTo moves the cursor to offset, clamped to the text, and
+// scrolls it into view.
+func (m *Model) moveCursorTo(offset int) {
+	if len(m.lineOffsets) == 0 {
+		return
+	}
+	offset = min(max(offset, 0), max(0, len(m.textRunes)-1))
+	m.cursorLine = m.lineOfOffset(offset)
+	m.cursorCol = min(offset-m.lineOffsets[m.cursorLine], utf8.RuneCountInString(m.lines[m.cursorLine]))
+	m.scrollLineIntoView(m.cursorLine)
+}
+
+// moveCursorLines moves the cursor delta visual lines up or down,
+// keeping its column where the target line is long enough.
+func (m *Model) moveCursorLines(delta int) {
+	if len(m.lineOffsets) == 0 {
+		return
+	}
+	m.cursorLine = min(max(m.cursorLine+delta, 0), len(m.lineOffsets)-1)
+	lineLen := utf8.RuneCountInString(m.lines[m.cursorLine])
+	m.cursorCol = min(m.cursorCol, max(0, lineLen-1))
+	m.scrollLineIntoView(m.cursorLine)
+}
+
+// highlightCursor marks the cell under the cursor when it falls on the
+// given visual line, like highlightSelection does for a selection.
+func (m Model) highlightCursor(line string, lineIdx int, indexMap []int) string {
+	if lineIdx != m.cursorLine {
+		return line
+	}
+	col := m.cursorCol
+	if indexMap != nil && col < len(indexMap) {
+		col = indexMap[col]
+	}
+	runes := []rune(line)
+	if col < 0 || col >= len(runes) {
+		return line
+	}
+	return string(runes[:col]) + m.theme.applyCursor(string(runes[col])) + string(runes[col+1:])
+}
+
+// wordAtCursor returns the word under the cursor, or "" when the cursor
+// is not on a letter. Apostrophes and hyphens inside a word belong to
+// it.
+func (m Model) wordAtCursor() string {
+	offset := m.cursorOffset()
+	if offset >= len(m.textRunes) || !unicode.IsLetter(m.textRunes[offset]) {
+		return ""
+	}
+	from, to := offset, offset+1
+	for from > 0 && isWordRune(m.textRunes[from-1]) {
+		from--
+	}
+	for to < len(m.textRunes) && isWordRune(m.textRunes[to]) {
+		to++
+	}
+	return strings.Trim(string(m.textRunes[from:to]), "'’-")
+}
+
+// isWordRune reports whether r can be part of a word to look up.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || r == '\'' || r == '’' || r == '-'
+}
+
+// lookupAtCursor starts a dictionary lookup of the word under the
+// cursor. Outside cursor mode it enters the mode first, so the word can
+// be picked.
+func (m *Model) lookupAtCursor() {
+	if m.currentBook == nil {
+		m.setStatus(m.tr(i18n.MsgLookupNoBook))
+		return
+	}
+	if !m.cursorMode {
+		m.startCursor()
+		m.setStatus(m.tr(i18n.MsgLookupPickWord))
+		return
+	}
+	word := m.wordAtCursor()
+	if word == "" {
+		m.setStatus(m.tr(i18n.MsgLookupNoWord))
+		return
+	}
+	m.lookupWord = word
+	m.popupOpen = false
+	m.setStatus(m.tr(i18n.MsgLookupPending, word))
+	m.queueCmd(lookupCmd(word))
+}
+
+// handleLookupResult shows the definition from a finished lookup,
+// unless another lookup has been started since.
+func (m *Model) handleLookupResult(msg lookupMsg) {
+	if msg.word != m.lookupWord {
+		return
+	}
+	switch {
+	case errors.Is(msg.err, errNoDefinition):
+		m.setStatus(m.tr(i18n.MsgLookupNotFound, msg.word))
+	case msg.err != nil:
+		m.setStatus(m.tr(i18n.MsgLookupFailed, msg.err))
+	default:
+		title := msg.word
+		if msg.partOfSpeech != "" {
+			title = m.tr(i18n.LabelLookupTitle, msg.word, msg.partOfSpeech)
+		}
+		m.showPopup(title, msg.definition)
+	}
+}
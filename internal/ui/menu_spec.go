@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"golang.org/x/text/message"
+
+	"thujareader/internal/i18n"
+)
+
+// menuSpec declares a top-level menu. Menus are built from specs so
+// that adding, removing, or reordering items only touches
+// defaultMenuSpec, and so that extensions can inject items into a spec
+// before the model is constructed.
+type menuSpec struct {
+	id    menuID
+	label string
+	items []menuItemSpec
+}
+
+// menuItemSpec declares a single menu item. Labels are message keys
+// from package i18n.
+type menuItemSpec struct {
+	label   string
+	command commandID
+}
+
+// defaultMenuSpec is the built-in menu bar, in display order.
+var defaultMenuSpec = []menuSpec{
+	{
+		id:    menuFile,
+		label: i18n.MenuFile,
+		items: []menuItemSpec{
+			{label: i18n.MenuItemOpen, command: cmdOpen},
+			{label: i18n.MenuItemNewTab, command: cmdNewTab},
+			{label: i18n.MenuItemOpenClipboard, command: cmdOpenURL},
+			{label: i18n.MenuItemReload, command: cmdReloadBook},
+			{label: i18n.MenuItemCloseTab, command: cmdCloseTab},
+			{label: i18n.MenuItemRecentFiles, command: cmdRecentFiles},
+			{label: i18n.MenuItemClearRecentFiles, command: cmdClearRecentFiles},
+			{label: i18n.MenuItemExportText, command: cmdExportText},
+			{label: i18n.MenuItemExit, command: cmdExit},
+		},
+	},
+	{
+		id:    menuSearch,
+		label: i18n.MenuSearch,
+		items: []menuItemSpec{
+			{label: i18n.MenuItemFind, command: cmdFind},
+			{label: i18n.MenuItemFindPrevious, command: cmdFindPrevious},
+			{label: i18n.MenuItemTOC, command: cmdToc},
+			{label: i18n.MenuItemMatchAcrossLines, command: cmdToggleSearchWhitespace},
+			{label: i18n.MenuItemSearchMode, command: cmdSearchMode},
+			{label: i18n.MenuItemGotoPercent, command: cmdGotoPercent},
+			{label: i18n.MenuItemGotoChapter, command: cmdGotoChapter},
+			{label: i18n.MenuItemGotoLine, command: cmdGotoLine},
+			{label: i18n.MenuItemLookup, command: cmdLookup},
+			{label: i18n.MenuItemFootnote, command: cmdFootnote},
+		},
+	},
+	{
+		id:    menuView,
+		label: i18n.MenuView,
+		items: []menuItemSpec{
+			{label: i18n.MenuItemWordWrap, command: cmdToggleWordWrap},
+			{label: i18n.MenuItemSplitView, command: cmdSplitView},
+			{label: i18n.MenuItemZoomIn, command: cmdZoomIn},
+			{label: i18n.MenuItemZoomOut, command: cmdZoomOut},
+			{label: i18n.MenuItemZoomReset, command: cmdZoomReset},
+			{label: i18n.MenuItemNextTab, command: cmdNextTab},
+			{label: i18n.MenuItemPrevTab, command: cmdPrevTab},
+			{label: i18n.MenuItemSelectText, command: cmdSelectText},
+			{label: i18n.MenuItemAutoScroll, command: cmdAutoScroll},
+			{label: i18n.MenuItemSettings, command: cmdConfig},
+		},
+	},
+	{
+		id:    menuBookmarks,
+		label: i18n.MenuBookmarks,
+		items: []menuItemSpec{
+			{label: i18n.MenuItemManageBookmarks, command: cmdBookmarks},
+			{label: i18n.MenuItemAddBookmark, command: cmdAddBookmark},
+			{label: i18n.MenuItemDeleteBookmark, command: cmdDeleteBookmark},
+			{label: i18n.MenuItemRenameBookmark, command: cmdRenameBookmark},
+			{label: i18n.MenuItemExportBookmarks, command: cmdExportBookmarks},
+			{label: i18n.MenuItemAddNote, command: cmdAddNote},
+			{label: i18n.MenuItemManageNotes, command: cmdNotes},
+		},
+	},
+	{
+		id:    menuHelp,
+		label: i18n.MenuHelp,
+		items: []menuItemSpec{
+			{label: i18n.MenuItemHelpTopics, command: cmdHelp},
+			{label: i18n.MenuItemStats, command: cmdStats},
+			{label: i18n.MenuItemMetadata, command: cmdMetadata},
+		},
+	},
+}
+
+// buildMenus turns a menu spec into the model's menu list, translating
+// labels with p. The result does not share item slices with the spec.
+func buildMenus(spec []menuSpec, p *message.Printer) []menu {
+	menus := make([]menu, len(spec))
+	for i, ms := range spec {
+		items := make([]menuItem, len(ms.items))
+		for j, is := range ms.items {
+			items[j] = menuItem{label: p.Sprintf(is.label), command: is.command}
+		}
+		menus[i] = menu{id: ms.id, label: p.Sprintf(ms.label), items: items}
+	}
+	return menus
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"maps"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+
+	"thujareader/internal/i18n"
+)
+
+// metadataLines returns the lines of the book information screen for
+// the open book: its title, author and every Metadata entry, sorted by
+// name, with long values wrapped to width cells.
+func (m Model) metadataLines(width int) []string {
+	if m.currentBook == nil {
+		return nil
+	}
+	book := m.currentBook.Book
+	lines := []string{" " + book.Title, ""}
+	fields := [][2]string{{m.tr(i18n.LabelMetadataAuthor), book.Author}}
+	for _, name := range slices.Sorted(maps.Keys(book.Metadata)) {
+		fields = append(fields, [2]string{name, book.Metadata[name]})
+	}
+
+	nameWidth := 0
+	for _, f := range fields {
+		nameWidth = max(nameWidth, runewidth.StringWidth(f[0]))
+	}
+	indent := 3 + nameWidth + 2
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		wrapped, _ := wrapText([]rune(f[1]), max(minTextWidth, width-indent), true, 0)
+		for i, line := range wrapped {
+			name := ""
+			if i == 0 {
+				name = f[0]
+			}
+			lines = append(lines, "   "+runewidth.FillRight(name, nameWidth)+"  "+line)
+		}
+	}
+	if len(book.Metadata) == 0 {
+		lines = append(lines, "", "   "+m.tr(i18n.LabelMetadataNone))
+	}
+	return lines
+}
+
+// handleMetadataKey closes the book information screen on Esc or Enter
+// and swallows other keys while it is open.
+func (m *Model) handleMetadataKey(msg tea.KeyMsg) bool {
+	if msg.Type == tea.KeyEsc || msg.Type == tea.KeyEnter {
+		m.metadataOpen = false
+	}
+	return true
+}
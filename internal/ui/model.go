@@ -1,15 +1,30 @@
 package ui
 
 import (
+	"io"
+	"math"
+	"net/http"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mattn/go-runewidth"
 	"golang.org/x/term"
+	"golang.org/x/text/message"
 
+	"thujareader/internal/config"
+	"thujareader/internal/i18n"
 	"thujareader/internal/reader"
+	"thujareader/internal/render"
+	"thujareader/internal/state"
 )
 
 // menuID identifies a top-level menu.
@@ -37,9 +52,64 @@ const (
 	cmdHelp
 	cmdAddBookmark
 	cmdDeleteBookmark
+	cmdToggleSearchWhitespace
+	cmdClearRecentFiles
+	cmdGotoPercent
+	cmdGotoChapter
+	cmdOpenURL
+	cmdReloadBook
+	cmdSearchMode
+	cmdToggleWordWrap
+	cmdRenameBookmark
+	cmdNewTab
+	cmdCloseTab
+	cmdNextTab
+	cmdPrevTab
+	cmdSelectText
+	cmdZoomIn
+	cmdZoomOut
+	cmdZoomReset
+	cmdExportBookmarks
+	cmdAddNote
+	cmdNotes
+	cmdFindPrevious
+	cmdStats
+	cmdLookup
+	cmdAutoScroll
+	cmdConfig
+	cmdSplitView
+	cmdFootnote
+	cmdExportText
+	cmdGotoLine
+	cmdMetadata
 )
 
-// menuItem is a single item within a menu.
+// SearchMode selects how Find compares the search term with the text.
+type SearchMode int
+
+const (
+	// SearchExact matches the term literally, case-sensitively.
+	SearchExact SearchMode = iota
+	// SearchCaseInsensitive matches the term literally, ignoring case.
+	SearchCaseInsensitive
+	// SearchRegex treats the term as a Go regular expression.
+	SearchRegex
+)
+
+// label returns the i18n key naming the mode.
+func (s SearchMode) label() string {
+	switch s {
+	case SearchCaseInsensitive:
+		return i18n.LabelSearchIgnoreCase
+	case SearchRegex:
+		return i18n.LabelSearchRegex
+	}
+	return i18n.LabelSearchExact
+}
+
+// menuItem is a single item within a menu. Its keyboard hint is not
+// part of the label; it is looked up in Model.keyBindings when the menu
+// is rendered.
 type menuItem struct {
 	label   string
 	command commandID
@@ -54,10 +124,31 @@ type menu struct {
 
 // Model holds UI state for the TUI shell emulating DOS edit.exe.
 type Model struct {
+	// StatusProvider, when set, lets external code such as a plugin
+	// add text to the status bar, e.g. a dictionary lookup. It is called
+	// on every render with a copy of the open book (nil when none is
+	// open) and the reading position; its text is shown before the
+	// location.
+	StatusProvider func(book *reader.Book, pos reader.Position) string
+
 	width  int
 	height int
 
 	theme Theme
+	// themeName is the Config.ThemeOverride the theme was resolved from.
+	themeName string
+	// autoTheme mirrors Config.AutoTheme: the theme follows colorScheme.
+	autoTheme bool
+
+	// defaultLibraryPath pre-fills the Open prompt so that users with a
+	// single book directory only need to type the file name.
+	defaultLibraryPath string
+	// bookshelfScanDepth mirrors Config.BookshelfScanDepth and is passed
+	// to reader.ScanDirectory when listing books in a directory.
+	bookshelfScanDepth int
+	// lastOpenDir is the directory of the most recently opened book; it
+	// pre-fills the Open prompt in preference to defaultLibraryPath.
+	lastOpenDir string
 
 	// unifiedReader is the shared entry point for loading books from
 	// disk. It is used both for CLI-argument opens and the in-app
@@ -83,24 +174,101 @@ type Model struct {
 	// can reflect the current chapter and percentage.
 	currentPos reader.Position
 
+	// Reading speed: the session starts when a book is opened and
+	// restarts after every jump, so only scrolling counts as reading,
+	// and after every pause longer than maxReadingPause, so idle time
+	// does not. lastReadTime is when the position last changed.
+	// readingCPM is the characters per minute measured in the latest
+	// session long enough to tell; zero means unknown.
+	sessionStartTime   time.Time
+	sessionStartOffset int
+	lastReadTime       time.Time
+	readingCPM         float64
+
 	// TOC dialog state.
 	tocOpen  bool
 	tocIndex int
+	// tocTop is the first TOC entry shown when the list is longer than
+	// the dialog.
+	tocTop int
 
 	// Bookmarks dialog state and in-memory storage.
 	bookmarks     map[reader.BookID][]reader.Bookmark
 	bookmarksOpen bool
 	bookmarkIndex int
+	// bookmarkTop is the first bookmark shown when the list is longer
+	// than the dialog.
+	bookmarkTop int
+
+	// Notes and the notes dialog state. The note editor edits
+	// noteBuffer, the text of the note at noteEditIndex in the open
+	// book's notes, or of a new note at notePos when it is -1.
+	notes         map[reader.BookID][]reader.Note
+	notesOpen     bool
+	noteIndex     int
+	noteTop       int
+	noteEditing   bool
+	noteEditIndex int
+	notePos       reader.Position
+	noteBuffer    []rune
+	noteCursor    int
+
+	// positions remembers the last reading position per book; the open
+	// book's entry is refreshed when another book replaces it and when
+	// positions are exported.
+	positions map[reader.BookID]reader.Position
+
+	// browserOpen shows the Open dialog's file browser.
+	browserOpen bool
+	browser     FileBrowserModel
 
 	// Recent files list and dialog state.
 	recentFiles []string
 	recentOpen  bool
 	recentIndex int
+	// recentTop is the first recent file shown when the list is longer
+	// than the dialog.
+	recentTop   int
 	recentLimit int
+	// recentFileMtimes caches the modification time of each entry in
+	// recentFiles, refreshed when the dialog opens. A zero time marks a
+	// file that no longer exists.
+	recentFileMtimes []time.Time
+
+	// library holds the bookshelf details of every book opened so far,
+	// by path. The bookshelf lists the recent files while no book is
+	// open; shelfIndex is the selected row and shelfTop the first shown.
+	library    map[string]state.BookInfo
+	shelfIndex int
+	shelfTop   int
 
 	// Search state for Find / Find Next.
 	lastSearch       string
 	lastSearchOffset int // rune offset of last match start; -1 if none
+	// highlightedMatches caches the rune offsets of every occurrence of
+	// lastSearch in the book so that View can highlight all visible
+	// matches, not just the focused one. It is computed lazily on the
+	// first search for a term and cleared on a new search or book change.
+	highlightedMatches []int
+	// highlightedLengths holds the rune length of each entry in
+	// highlightedMatches. Lengths can differ from the term length when
+	// whitespace-normalized matching spans several whitespace runes.
+	highlightedLengths []int
+	// searchNormalizeSpace enables whitespace-normalized matching: runs
+	// of whitespace (including newlines) in both the book text and the
+	// term are collapsed to a single space before comparing, so a term
+	// can match across paragraph and line boundaries. It is off until
+	// toggled (Match Across Lines), so exact searches stay exact.
+	searchNormalizeSpace bool
+	// searchMode is the active comparison mode, cycled by cmdSearchMode.
+	searchMode SearchMode
+	// searchNoMatch shows a centered "no matches" overlay until the next
+	// key press, since the status bar message alone is easy to miss.
+	searchNoMatch bool
+
+	// keyBindings maps commands to their direct keys; see
+	// defaultKeyBindings.
+	keyBindings map[commandID]string
 
 	menus       []menu
 	activeMenu  int  // index into menus, -1 when no menu is active
@@ -108,15 +276,170 @@ type Model struct {
 	menuOpen    bool // whether menu bar interaction is active
 	statusLine  string
 	statusDirty bool
+	// statusExpiry is when a transient status message (see
+	// setTransientStatus) reverts to the default hint; zero if the
+	// current message is permanent.
+	statusExpiry time.Time
 
 	// inputMode indicates that the UI is currently collecting a single
 	// line of text input from the user (e.g. for a file path).
 	inputMode   bool
 	inputPrompt string
 	inputBuffer []rune
+	// inputCursor is the insertion point in inputBuffer, in runes
+	// (0..len(inputBuffer)).
+	inputCursor int
 	// pendingCommand records which command should be executed when the
 	// current line input is confirmed (e.g. cmdOpen).
 	pendingCommand commandID
+
+	// confirmOpen indicates a yes/no question is shown; confirmCommand
+	// is carried out via runConfirmed when the user answers "y".
+	confirmOpen    bool
+	confirmPrompt  string
+	confirmCommand commandID
+
+	// inputHistory keeps previously confirmed inputs per command, oldest
+	// first, so Up/Down can recall them. inputHistoryPos is the entry
+	// currently shown, or -1 while editing a fresh line.
+	inputHistory     map[commandID][]string
+	inputHistoryPos  int
+	inputHistorySize int
+
+	// tabCompletionEnabled enables Tab completion of file paths in the
+	// Open prompt.
+	tabCompletionEnabled bool
+
+	// scrollLines is how many lines a mouse-wheel step scrolls.
+	scrollLines int
+
+	// acceptDrops opens files dropped onto the terminal window; see
+	// SetAcceptDrops.
+	acceptDrops bool
+
+	// autoScroll advances the text by a line every autoScrollDelay
+	// (cmdAutoScroll). autoScrollGen tells the ticks of the current run
+	// from those of a stopped one still in flight.
+	autoScroll      bool
+	autoScrollDelay time.Duration
+	autoScrollGen   int
+
+	// splitView shows the table of contents in a pane left of the text.
+	splitView bool
+
+	// leftMargin and rightMargin are blank columns kept beside the text;
+	// see margins.
+	leftMargin  int
+	rightMargin int
+
+	// zoom narrows the text column to simulate a larger font, since
+	// the terminal font size cannot be changed; see textColumnWidth.
+	zoom int
+
+	// tabWidth is the distance between tab stops; tabs stay in m.lines
+	// and are expanded to spaces only when rendered (see expandTabs).
+	tabWidth int
+
+	// wordWrap breaks lines at spaces instead of at the window edge; see
+	// wrapText.
+	wordWrap bool
+
+	// gKeyJumpsToEnd makes "G" jump to the end of the book (as in Vim)
+	// instead of opening the Goto % prompt.
+	gKeyJumpsToEnd bool
+
+	// loadingInProgress is set while a book is parsed in the background;
+	// loadingPath names the file and spinnerFrame animates the status bar.
+	loadingInProgress bool
+	loadingPath       string
+	spinnerFrame      int
+	// reloadPos, when set, is the position to restore once the pending
+	// load finishes; cmdReloadBook uses it to keep the reader's place.
+	reloadPos *reader.Position
+
+	// books holds the book of every tab, in tab order;
+	// books[activeBookIndex] is the one shown as currentBook. The other
+	// tabs keep their reading position in positions.
+	books           []reader.LoadedBook
+	activeBookIndex int
+	// statsOpen shows the open book's statistics in the main area.
+	statsOpen bool
+
+	// metadataOpen shows the open book's title, author and metadata in
+	// the main area (cmdMetadata).
+	metadataOpen bool
+
+	// config is the configuration in effect and configPath the file the
+	// settings dialog (configOpen) saves to. The dialog edits
+	// configDraft, with configIndex the selected row.
+	config      config.Config
+	configPath  string
+	configOpen  bool
+	configDraft config.Config
+	configIndex int
+	configTop   int
+
+	// isRTL is set for books in right-to-left scripts such as Arabic
+	// and Hebrew, whose lines are right-aligned.
+	isRTL bool
+
+	// helpOpen shows the help screen in the main area, scrolled to
+	// helpTopLine.
+	helpOpen    bool
+	helpTopLine int
+
+	// Visual selection mode: selectStart is where the selection was
+	// started and selectEnd the end moved by the arrow keys, both as
+	// rune offsets into textRunes (see selectionRange).
+	selecting   bool
+	selectStart int
+	selectEnd   int
+
+	// Cursor mode (entered with Enter): cursorLine is the visual line
+	// and cursorCol the rune index within it of the movable cursor.
+	cursorMode bool
+	cursorLine int
+	cursorCol  int
+
+	// lookupWord is the word of the latest dictionary lookup
+	// (cmdLookup).
+	lookupWord string
+
+	// popupOpen shows popupText under popupTitle in a box over the main
+	// area, e.g. a definition or a footnote; see showPopup.
+	popupOpen  bool
+	popupTitle string
+	popupText  string
+
+	// textExport is the plain text export being written (cmdExportText),
+	// or nil.
+	textExport *textExport
+
+	// dailyProgress maps a local date to the characters scrolled past
+	// that day; see countScrolledText.
+	dailyProgress map[string]int
+
+	// newTabPending makes the next opened book go to a new tab instead
+	// of replacing the active one (cmdNewTab).
+	newTabPending bool
+
+	// escPressed and escTime track a recent Esc key press so that an
+	// Esc-prefixed key can be treated as Alt+key.
+	escPressed bool
+	escTime    time.Time
+
+	// colorScheme is the terminal background found by
+	// DetectColorScheme ("light", "dark" or "" if unknown). It is not
+	// re-detected on config reload because Bubble Tea owns the terminal
+	// input by then.
+	colorScheme string
+
+	// printer formats user-visible strings (see package i18n) in the
+	// configured language.
+	printer *message.Printer
+
+	// cmds collects commands queued during the current Update call.
+	cmds []tea.Cmd
 }
 
 // NewModel constructs the initial UI model without a pre-loaded book.
@@ -125,8 +448,23 @@ func NewModel() Model {
 }
 
 // NewModelWithInitialBook constructs the initial UI model, optionally
-// pre-populated with a book that was opened via CLI arguments.
+// pre-populated with a book that was opened via CLI arguments. Settings
+// come from config.DefaultConfig.
 func NewModelWithInitialBook(book *reader.LoadedBook) Model {
+	return NewModelWithConfig(config.DefaultConfig(), book, nil, nil)
+}
+
+// NewModelWithInitialBookAndBookmarks constructs the initial UI model
+// and pre-populates it with a book (if any) and a set of bookmarks
+// loaded from persisted state.
+func NewModelWithInitialBookAndBookmarks(book *reader.LoadedBook, bookmarks map[reader.BookID][]reader.Bookmark) Model {
+	return NewModelWithConfig(config.DefaultConfig(), book, bookmarks, nil)
+}
+
+// NewModelWithConfig constructs the initial UI model with settings from
+// cfg, optionally pre-populated with a book, persisted bookmarks, and
+// persisted reading positions.
+func NewModelWithConfig(cfg config.Config, book *reader.LoadedBook, bookmarks map[reader.BookID][]reader.Bookmark, positions map[reader.BookID]reader.Position) Model {
 	m := Model{
 		// Start with a reasonable default size so that the UI can render
 		// even if no WindowSizeMsg is delivered (which can happen on some
@@ -136,51 +474,25 @@ func NewModelWithInitialBook(book *reader.LoadedBook) Model {
 		height:        25,
 		theme:         ThemeFromEnv(),
 		unifiedReader: reader.NewDefaultUnifiedReader(),
-		menus: []menu{
-			{
-				id:    menuFile,
-				label: "File",
-				items: []menuItem{
-					{label: "Open...  F3", command: cmdOpen},
-					{label: "Recent Files", command: cmdRecentFiles},
-					{label: "Exit      Alt+F X", command: cmdExit},
-				},
-			},
-			{
-				id:    menuSearch,
-				label: "Search",
-				items: []menuItem{
-					{label: "Find...  F7", command: cmdFind},
-					{label: "TOC", command: cmdToc},
-				},
-			},
-			{
-				id:    menuView,
-				label: "View",
-				items: []menuItem{},
-			},
-			{
-				id:    menuBookmarks,
-				label: "Bookmarks",
-				items: []menuItem{
-					{label: "Manage Bookmarks", command: cmdBookmarks},
-					{label: "Add Bookmark  F2", command: cmdAddBookmark},
-					{label: "Delete Bookmark", command: cmdDeleteBookmark},
-				},
-			},
-			{
-				id:    menuHelp,
-				label: "Help",
-				items: []menuItem{
-					{label: "Help Topics  F1", command: cmdHelp},
-				},
-			},
-		},
-		activeMenu:  -1,
-		activeItem:  0,
-		statusLine:  "Press F10 or Alt key combinations to open menus. F1 for Help.",
-		bookmarks:   make(map[reader.BookID][]reader.Bookmark),
-		recentLimit: 10,
+		activeMenu:    -1,
+		activeItem:    0,
+		bookmarks:     make(map[reader.BookID][]reader.Bookmark),
+		recentLimit:   10,
+
+		inputHistory:     make(map[commandID][]string),
+		inputHistoryPos:  -1,
+		inputHistorySize: 20,
+		scrollLines:      3,
+		tabWidth:         4,
+		autoScrollDelay:  3 * time.Second,
+	}
+	m.applyConfig(cfg)
+	m.statusLine = m.tr(i18n.MsgWelcome)
+	if bookmarks != nil {
+		m.bookmarks = bookmarks
+	}
+	if positions != nil {
+		m.positions = positions
 	}
 
 	// Try to detect the actual terminal size at startup so that initial
@@ -196,23 +508,19 @@ func NewModelWithInitialBook(book *reader.LoadedBook) Model {
 	}
 
 	if book != nil {
-		m.setBook(*book)
+		m.showBook(*book, false)
+		if m.restoreSavedPosition() {
+			m.setStatus(m.tr(i18n.MsgPositionClamped, m.statusLine))
+		}
+		// Books opened from the command line count as recently opened too.
+		if book.SourcePath != "" {
+			m.addOpenedFile(book.SourcePath)
+		}
 	}
 
 	return m
 }
 
-// NewModelWithInitialBookAndBookmarks constructs the initial UI model
-// and pre-populates it with a book (if any) and a set of bookmarks
-// loaded from persisted state.
-func NewModelWithInitialBookAndBookmarks(book *reader.LoadedBook, bookmarks map[reader.BookID][]reader.Bookmark) Model {
-	m := NewModelWithInitialBook(book)
-	if bookmarks != nil {
-		m.bookmarks = bookmarks
-	}
-	return m
-}
-
 // detectTerminalSize returns the current terminal width and height in
 // cells, if stdout is attached to a TTY and the size can be queried.
 // It is a best-effort helper used to initialize the model before any
@@ -247,6 +555,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.reflowWrappedLines()
 		return m, nil
 
+	case ConfigReloadMsg:
+		if msg.Err != nil {
+			m.setStatus(m.tr(i18n.MsgConfigReloadFailed, msg.Err))
+			return m, nil
+		}
+		m.applyConfig(msg.Config)
+		m.reflowWrappedLines()
+		m.setStatus(m.tr(i18n.MsgConfigReloaded))
+		return m, nil
+
 	case tea.KeyMsg:
 		// Always allow Ctrl+C to quit.
 		if msg.Type == tea.KeyCtrlC {
@@ -257,15 +575,123 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// for the Open command), route all key presses through the input
 		// handler instead of the normal menu/keybinding logic.
 		if m.inputMode {
-			if m.handleInputKey(msg) {
-				return m, nil
+			m.handleInputKey(msg)
+			return m, m.takeCmds()
+		}
+		if m.noteEditing {
+			m.handleNoteEditKey(msg)
+			return m, m.takeCmds()
+		}
+
+		// A pending yes/no question swallows the next key press.
+		if m.confirmOpen {
+			m.handleConfirmKey(msg)
+			return m, m.takeCmds()
+		}
+
+		// A path pasted while accepting drops is a file dropped onto
+		// the window.
+		if msg.Paste && m.acceptDrops {
+			if path, ok := droppedPath(string(msg.Runes)); ok {
+				m.openPath(path)
+				return m, m.takeCmds()
 			}
+		}
+
+		m.handleKey(msg)
+		return m, m.takeCmds()
+
+	case tea.MouseMsg:
+		m.handleMouse(msg)
+		return m, m.takeCmds()
+
+	case bookLoadedMsg:
+		// Ignore results of loads that were superseded by a newer open.
+		if !m.loadingInProgress || msg.path != m.loadingPath {
+			return m, m.takeCmds()
+		}
+		m.loadingInProgress = false
+		m.loadingPath = ""
+		reloadPos := m.reloadPos
+		m.reloadPos = nil
+		if msg.err != nil {
+			m.setStatus(m.tr(i18n.MsgOpenFailed, msg.err))
+			return m, m.takeCmds()
+		}
+		m.showBook(msg.book, msg.newTab)
+		m.addOpenedFile(msg.path)
+		if reloadPos != nil {
+			m.jumpToPosition(m.clampPosition(*reloadPos))
+			m.setStatus(m.tr(i18n.MsgBookReloaded, msg.book.Book.Title))
+			return m, m.takeCmds()
+		}
+		clamped := m.restoreSavedPosition()
+		status := m.tr(i18n.MsgBookOpened, msg.book.Book.Title)
+		if msg.viaSymlink {
+			status = m.tr(i18n.MsgBookOpenedViaSymlink, msg.path, msg.book.Book.Title)
+		}
+		if clamped {
+			status = m.tr(i18n.MsgPositionClamped, status)
+		}
+		if warnings := reader.ValidateBook(*m.currentBook); len(warnings) > 0 {
+			status = m.tr(i18n.MsgBookWarnings, status, strings.Join(warnings, "; "))
+		}
+		m.setStatus(status)
+		return m, m.takeCmds()
+
+	case escTimeoutMsg:
+		if m.escPressed && time.Since(m.escTime) >= escSequenceWindow {
+			m.escPressed = false
+		}
+		return m, nil
+
+	case clipboardMsg:
+		if msg.err != nil {
+			m.setStatus(m.tr(i18n.MsgClipboardFailed, msg.err))
+			return m, nil
+		}
+		path := firstLine(msg.text)
+		if path == "" {
+			m.setStatus(m.tr(i18n.MsgClipboardEmpty))
 			return m, nil
 		}
+		m.openPath(path)
+		return m, m.takeCmds()
+
+	case lookupMsg:
+		m.handleLookupResult(msg)
+		return m, nil
+
+	case exportTextMsg:
+		m.handleExportTextResult(msg)
+		return m, m.takeCmds()
+
+	case autoScrollTickMsg:
+		m.handleAutoScrollTick(msg)
+		return m, m.takeCmds()
+
+	case clipboardWrittenMsg:
+		if msg.err != nil {
+			m.setStatus(m.tr(i18n.MsgClipboardFailed, msg.err))
+		} else {
+			m.setTransientStatus(m.tr(i18n.MsgCopied, msg.n))
+		}
+		return m, m.takeCmds()
 
-		if m.handleKey(msg) {
+	case statusExpiredMsg:
+		// A newer message may have replaced the transient one or pushed
+		// its expiry further out; only revert once it is really due.
+		if !m.statusExpiry.IsZero() && !time.Now().Before(m.statusExpiry) {
+			m.setStatus(m.tr(i18n.MsgWelcome))
+		}
+		return m, nil
+
+	case spinnerTickMsg:
+		if !m.loadingInProgress {
 			return m, nil
 		}
+		m.spinnerFrame = (m.spinnerFrame + 1) % len(spinnerFrames)
+		return m, spinnerTick()
 	}
 
 	return m, nil
@@ -273,10 +699,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *Model) openMenuByAltKey(ch rune) {
 	for i, menu := range m.menus {
-		if len(menu.label) == 0 {
+		// The accelerator is the first rune, which may be multi-byte in
+		// localized labels.
+		first, size := utf8.DecodeRuneInString(menu.label)
+		if size == 0 {
 			continue
 		}
-		if strings.ToLower(string(menu.label[0])) == strings.ToLower(string(ch)) {
+		if unicode.ToLower(first) == unicode.ToLower(ch) {
 			m.menuOpen = true
 			m.activeMenu = i
 			m.activeItem = 0
@@ -286,6 +715,45 @@ func (m *Model) openMenuByAltKey(ch rune) {
 }
 
 func (m *Model) handleKey(msg tea.KeyMsg) bool {
+	// Any key dismisses the "no matches" overlay.
+	if m.searchNoMatch {
+		m.searchNoMatch = false
+		return true
+	}
+	// Any key closes the popup.
+	if m.popupOpen {
+		m.closePopup()
+		return true
+	}
+
+	// Terminals that send Alt as an Esc prefix (e.g. xterm with
+	// metaSendsEscape) deliver Alt+F as Esc followed by "f". Remember
+	// each Esc briefly and treat a single rune arriving within the
+	// window as an Alt combination. The Esc itself is still handled
+	// normally below.
+	if msg.Type == tea.KeyEsc {
+		m.escPressed = true
+		m.escTime = time.Now()
+		m.queueCmd(tea.Tick(escSequenceWindow, func(time.Time) tea.Msg {
+			return escTimeoutMsg{}
+		}))
+	} else if m.escPressed {
+		m.escPressed = false
+		if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && time.Since(m.escTime) <= escSequenceWindow {
+			m.openMenuByAltKey(msg.Runes[0])
+			return true
+		}
+	}
+
+	// Keys bound in config.json come before the built-in ones. Letter
+	// keys are left to reading mode so they still reach dialogs.
+	if msg.Type != tea.KeyRunes || msg.Alt {
+		if cmd, ok := m.overriddenCommand(msg); ok {
+			m.executeCommand(cmd)
+			return true
+		}
+	}
+
 	switch msg.Type {
 	case tea.KeyF10:
 		// Toggle menu bar interaction.
@@ -309,15 +777,43 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 	case tea.KeyF3:
 		m.executeCommand(cmdOpen)
 		return true
+	case tea.KeyF5:
+		m.executeCommand(cmdReloadBook)
+		return true
+	case tea.KeyCtrlG:
+		m.executeCommand(cmdGotoChapter)
+		return true
+	case tea.KeyCtrlV:
+		m.executeCommand(cmdOpenURL)
+		return true
+	case tea.KeyCtrlT:
+		m.executeCommand(cmdNewTab)
+		return true
+	case tea.KeyCtrlW:
+		m.executeCommand(cmdCloseTab)
+		return true
+	case tea.KeyCtrlB:
+		m.executeCommand(cmdSplitView)
+		return true
+	case tea.KeyCtrlPgDown:
+		m.executeCommand(cmdNextTab)
+		return true
+	case tea.KeyCtrlPgUp:
+		m.executeCommand(cmdPrevTab)
+		return true
 	case tea.KeyF7:
 		// F7 either opens the Find dialog or, if a previous search term
 		// exists, jumps to the next match.
 		if !m.inputMode && m.lastSearch != "" {
-			m.performSearch(m.lastSearch, false)
+			m.performSearch(m.lastSearch, false, false)
 		} else {
 			m.executeCommand(cmdFind)
 		}
 		return true
+	case tea.KeyF19:
+		// Terminals report Shift+F7 as F19.
+		m.executeCommand(cmdFindPrevious)
+		return true
 	}
 
 	// Alt+<letter> opens corresponding menu (e.g., Alt+F for File).
@@ -330,8 +826,20 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 		// When the menu is not open, either handle TOC navigation when
 		// the TOC dialog is active or perform normal reading/view
 		// navigation.
-		if m.currentBook == nil {
-			return false
+		if m.helpOpen {
+			return m.handleHelpKey(msg)
+		}
+		if m.statsOpen {
+			return m.handleStatsKey(msg)
+		}
+		if m.metadataOpen {
+			return m.handleMetadataKey(msg)
+		}
+		if m.configOpen {
+			return m.handleConfigKey(msg)
+		}
+		if m.browserOpen {
+			return m.handleBrowserKey(msg)
 		}
 
 		// TOC dialog navigation when open.
@@ -344,6 +852,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 				if m.tocIndex > 0 {
 					m.tocIndex--
 				}
+				m.tocTop = scrollTopFor(m.tocTop, m.tocIndex, m.dialogListRows())
 				return true
 			case tea.KeyDown:
 				if m.currentBook != nil {
@@ -352,6 +861,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 						m.tocIndex++
 					}
 				}
+				m.tocTop = scrollTopFor(m.tocTop, m.tocIndex, m.dialogListRows())
 				return true
 			case tea.KeyEnter:
 				if m.currentBook != nil && m.tocIndex >= 0 && m.tocIndex < len(m.currentBook.TOC) {
@@ -374,6 +884,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 				if m.bookmarkIndex > 0 {
 					m.bookmarkIndex--
 				}
+				m.bookmarkTop = scrollTopFor(m.bookmarkTop, m.bookmarkIndex, m.visibleLineCount())
 				return true
 			case tea.KeyDown:
 				current := m.currentBookmarks()
@@ -383,6 +894,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 				if m.bookmarkIndex < len(current)-1 {
 					m.bookmarkIndex++
 				}
+				m.bookmarkTop = scrollTopFor(m.bookmarkTop, m.bookmarkIndex, m.visibleLineCount())
 				return true
 			case tea.KeyEnter:
 				current := m.currentBookmarks()
@@ -397,12 +909,21 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 				bm := current[m.bookmarkIndex]
 				m.jumpToPosition(bm.Pos)
 				m.bookmarksOpen = false
-				m.setStatus("Jumped to bookmark: " + bm.Name)
+				m.setStatus(m.tr(i18n.MsgJumpedToBookmark, bm.Name))
 				return true
+			case tea.KeyRunes:
+				if len(msg.Runes) == 1 && (msg.Runes[0] == 'r' || msg.Runes[0] == 'R') {
+					m.executeCommand(cmdRenameBookmark)
+					return true
+				}
 			}
 			return false
 		}
 
+		if m.notesOpen {
+			return m.handleNotesKey(msg)
+		}
+
 		// Recent files dialog navigation when open.
 		if m.recentOpen {
 			switch msg.Type {
@@ -413,6 +934,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 				if m.recentIndex > 0 {
 					m.recentIndex--
 				}
+				m.recentTop = scrollTopFor(m.recentTop, m.recentIndex, m.visibleLineCount())
 				return true
 			case tea.KeyDown:
 				if len(m.recentFiles) == 0 {
@@ -421,6 +943,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 				if m.recentIndex < len(m.recentFiles)-1 {
 					m.recentIndex++
 				}
+				m.recentTop = scrollTopFor(m.recentTop, m.recentIndex, m.visibleLineCount())
 				return true
 			case tea.KeyEnter:
 				if len(m.recentFiles) == 0 {
@@ -435,12 +958,46 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 				m.recentOpen = false
 				m.openPath(path)
 				return true
+			case tea.KeyDelete:
+				m.removeRecentFile(m.recentIndex)
+				return true
+			case tea.KeyCtrlR:
+				m.executeCommand(cmdClearRecentFiles)
+				return true
+			case tea.KeyRunes:
+				if len(msg.Runes) == 1 && (msg.Runes[0] == 'd' || msg.Runes[0] == 'D') {
+					m.removeRecentFile(m.recentIndex)
+					return true
+				}
+				if len(msg.Runes) == 1 && (msg.Runes[0] == 'c' || msg.Runes[0] == 'C') {
+					m.executeCommand(cmdClearRecentFiles)
+					return true
+				}
 			}
 			return false
 		}
 
 		// Normal reading navigation when no modal dialog (like TOC) is
 		// active.
+		if m.currentBook == nil {
+			return m.handleShelfKey(msg)
+		}
+		if m.autoScroll && isArrowKey(msg) {
+			m.stopAutoScroll()
+			return true
+		}
+		if m.selecting && m.handleSelectionKey(msg) {
+			return true
+		}
+		if m.cursorMode && m.handleCursorKey(msg) {
+			return true
+		}
+		if msg.Type == tea.KeyRunes {
+			if cmd, ok := m.overriddenCommand(msg); ok {
+				m.executeCommand(cmd)
+				return true
+			}
+		}
 		switch msg.Type {
 		case tea.KeyUp:
 			if m.topLine > 0 {
@@ -452,6 +1009,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 			if m.topLine < len(m.lines)-1 {
 				m.topLine++
 				m.updateCurrentPositionFromTopLine()
+				m.countScrolledText(m.topLine - 1)
 			}
 			return true
 		case tea.KeyPgUp:
@@ -474,26 +1032,92 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 			}
 			maxTop := max(0, len(m.lines)-1)
 			if m.topLine < maxTop {
+				fromLine := m.topLine
 				m.topLine += page
 				if m.topLine > maxTop {
 					m.topLine = maxTop
 				}
 				m.updateCurrentPositionFromTopLine()
+				m.countScrolledText(fromLine)
+			}
+			return true
+		case tea.KeyCtrlU:
+			// Half a page, as in less(1).
+			half := max(1, m.visibleLineCount()/2)
+			if m.topLine > 0 {
+				m.topLine = max(0, m.topLine-half)
+				m.updateCurrentPositionFromTopLine()
+			}
+			return true
+		case tea.KeyCtrlD:
+			half := max(1, m.visibleLineCount()/2)
+			maxTop := max(0, len(m.lines)-1)
+			if m.topLine < maxTop {
+				fromLine := m.topLine
+				m.topLine = min(maxTop, m.topLine+half)
+				m.updateCurrentPositionFromTopLine()
+				m.countScrolledText(fromLine)
 			}
 			return true
 		case tea.KeyHome:
 			if m.topLine != 0 {
 				m.topLine = 0
 				m.updateCurrentPositionFromTopLine()
+				m.startReadingSession()
 			}
 			return true
 		case tea.KeyEnd:
-			maxTop := max(0, len(m.lines)-1)
-			if m.topLine != maxTop {
-				m.topLine = maxTop
-				m.updateCurrentPositionFromTopLine()
-			}
+			m.scrollToEnd()
+			return true
+		case tea.KeyEnter:
+			m.startCursor()
 			return true
+		case tea.KeyRunes:
+			if len(msg.Runes) == 1 && msg.Runes[0] == 'G' {
+				if m.gKeyJumpsToEnd {
+					m.scrollToEnd()
+				} else {
+					m.executeCommand(cmdGotoPercent)
+				}
+				return true
+			}
+			if len(msg.Runes) == 1 {
+				switch msg.Runes[0] {
+				case 'v':
+					m.executeCommand(cmdSelectText)
+					return true
+				case 'n':
+					m.executeCommand(cmdAddNote)
+					return true
+				case 'd':
+					m.executeCommand(cmdLookup)
+					return true
+				case 'a':
+					m.executeCommand(cmdAutoScroll)
+					return true
+				case 'f':
+					m.executeCommand(cmdFootnote)
+					return true
+				case 'c':
+					m.executeCommand(cmdGotoChapter)
+					return true
+				case ':':
+					m.executeCommand(cmdGotoLine)
+					return true
+				case 'i':
+					m.executeCommand(cmdMetadata)
+					return true
+				case '+':
+					m.executeCommand(cmdZoomIn)
+					return true
+				case '-':
+					m.executeCommand(cmdZoomOut)
+					return true
+				case '0':
+					m.executeCommand(cmdZoomReset)
+					return true
+				}
+			}
 		}
 		return false
 	}
@@ -545,7 +1169,77 @@ func (m *Model) handleKey(msg tea.KeyMsg) bool {
 }
 
 func (m *Model) executeCommand(cmd commandID) {
+	// A pending new tab only applies to the open that cmdNewTab starts;
+	// any other command means that open was abandoned.
+	m.newTabPending = false
+	// Any other command closes the help, statistics and book information
+	// screens and the settings dialog, which would hide its result.
+	if cmd != cmdHelp {
+		m.helpOpen = false
+	}
+	if cmd != cmdStats {
+		m.statsOpen = false
+	}
+	if cmd != cmdMetadata {
+		m.metadataOpen = false
+	}
+	if cmd != cmdConfig {
+		m.configOpen = false
+	}
 	switch cmd {
+	case cmdNewTab:
+		m.executeCommand(cmdOpen)
+		m.newTabPending = true
+	case cmdSelectText:
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.cursorMode = false
+		m.startSelection()
+	case cmdLookup:
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.lookupAtCursor()
+	case cmdAutoScroll:
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.toggleAutoScroll()
+	case cmdConfig:
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.openConfigEditor()
+	case cmdSplitView:
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.toggleSplitView()
+	case cmdFootnote:
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.showFootnote()
+	case cmdExportText:
+		if m.currentBook == nil {
+			m.setStatus(m.tr(i18n.MsgExportTextNoBook))
+			return
+		}
+		if m.textExport != nil {
+			m.setStatus(m.tr(i18n.MsgExportTextBusy))
+			return
+		}
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.startInput(cmdExportText, m.tr(i18n.PromptExportText))
+		m.setInput(m.defaultTextExportPath())
+	case cmdCloseTab:
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.closeTab()
+	case cmdNextTab, cmdPrevTab:
+		m.menuOpen = false
+		m.activeMenu = -1
+		delta := 1
+		if cmd == cmdPrevTab {
+			delta = -1
+		}
+		m.switchTab(delta)
 	case cmdOpen:
 		// When invoking the Open command from the menu, close the menu so
 		// that, after opening a file, the main area can display the book
@@ -553,56 +1247,88 @@ func (m *Model) executeCommand(cmd commandID) {
 		m.menuOpen = false
 		m.activeMenu = -1
 
-		// Enter a simple line-input mode where the user can type a file
-		// path to open. This is a minimal stand-in for a full file
-		// dialog and is sufficient for Phase 3.
-		m.inputMode = true
-		m.inputPrompt = "Open file: "
-		m.inputBuffer = m.inputBuffer[:0]
-		m.pendingCommand = cmdOpen
-		m.setStatus("Enter path to EPUB/FB2 file and press Enter.")
+		// Browse from the last used directory, falling back to the
+		// library and then the working directory. If none can be listed,
+		// fall back to typing a path.
+		m.browser = newFileBrowser(m.bookshelfScanDepth, m.unifiedReader.Extensions())
+		for _, dir := range []string{m.lastOpenDir, expandPath(m.defaultLibraryPath), "."} {
+			if dir == "" {
+				continue
+			}
+			if err := m.browser.chdir(dir); err == nil {
+				m.browserOpen = true
+				m.setStatus(m.tr(i18n.MsgBrowserHint))
+				return
+			}
+		}
+		m.startOpenPrompt("")
+	case cmdReloadBook:
+		if m.currentBook == nil || m.currentBook.SourcePath == "" {
+			m.setStatus(m.tr(i18n.MsgReloadNoBook))
+			return
+		}
+		m.menuOpen = false
+		m.activeMenu = -1
+		savedPos := m.currentPos
+		m.openPath(m.currentBook.SourcePath)
+		if m.loadingInProgress {
+			m.reloadPos = &savedPos
+		}
+	case cmdOpenURL:
+		// The clipboard holds a path or URL; openPath takes it from
+		// there once the read completes (see clipboardMsg).
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.setStatus(m.tr(i18n.MsgClipboardReading))
+		m.queueCmd(readClipboardCmd())
 	case cmdExit:
-		m.setStatus("Exit: press Alt+F then X or Ctrl+C to quit.")
+		m.setStatus(m.tr(i18n.MsgExitHint))
 	case cmdFind:
 		// Enter search input mode. Reuse the simple one-line input UI
 		// but distinguish via pendingCommand.
-		m.inputMode = true
-		m.inputPrompt = "Find: "
-		m.inputBuffer = m.inputBuffer[:0]
-		m.pendingCommand = cmdFind
-		m.setStatus("Enter search text and press Enter. Press Esc to cancel.")
+		m.startInput(cmdFind, m.tr(i18n.PromptFind))
+		m.setStatus(m.tr(i18n.MsgFindHint))
 	case cmdToc:
 		if m.currentBook == nil || len(m.currentBook.TOC) == 0 {
-			m.setStatus("TOC: no table of contents available for this book.")
+			m.setStatus(m.tr(i18n.MsgTOCEmpty))
 			return
 		}
 		// Open TOC dialog starting at first entry.
 		m.tocOpen = true
 		m.tocIndex = 0
+		m.tocTop = 0
 		m.menuOpen = false
 		m.activeMenu = -1
-		m.setStatus("TOC: Use ↑/↓ to select, Enter to jump, Esc to cancel.")
+		m.setStatus(m.tr(i18n.MsgTOCHint))
 	case cmdBookmarks:
 		if m.currentBook == nil {
-			m.setStatus("Bookmarks: no book is currently open.")
+			m.setStatus(m.tr(i18n.MsgBookmarksNoBook))
 			return
 		}
 		current := m.currentBookmarks()
 		if len(current) == 0 {
-			m.setStatus("Bookmarks: no bookmarks for this book.")
+			m.setStatus(m.tr(i18n.MsgBookmarksEmpty))
 			return
 		}
 		m.bookmarksOpen = true
 		m.bookmarkIndex = 0
+		m.bookmarkTop = 0
 		m.menuOpen = false
 		m.activeMenu = -1
-		m.setStatus("Bookmarks: Use ↑/↓ to select, Enter to jump, Esc to cancel.")
+		m.setStatus(m.tr(i18n.MsgBookmarksHint))
 	case cmdAddBookmark:
 		if m.currentBook == nil {
-			m.setStatus("Cannot add bookmark: no book is open.")
+			m.setStatus(m.tr(i18n.MsgBookmarkNoBook))
 			return
 		}
-		name := "Bookmark " + itoa(len(m.currentBookmarks())+1)
+		// Number the user's own bookmarks; TOC bookmarks have names.
+		n := 1
+		for _, bm := range m.currentBookmarks() {
+			if !bm.FromTOC {
+				n++
+			}
+		}
+		name := m.tr(i18n.LabelBookmarkName, n)
 		bm := reader.Bookmark{
 			Name:   name,
 			BookID: m.currentBook.Book.ID,
@@ -614,7 +1340,7 @@ func (m *Model) executeCommand(cmd commandID) {
 			m.bookmarks = make(map[reader.BookID][]reader.Bookmark)
 		}
 		m.bookmarks[m.currentBook.Book.ID] = list
-		m.setStatus("Added bookmark: " + name)
+		m.setTransientStatus(m.tr(i18n.MsgBookmarkAdded, name))
 	case cmdDeleteBookmark:
 		if !m.bookmarksOpen || m.currentBook == nil {
 			return
@@ -623,59 +1349,420 @@ func (m *Model) executeCommand(cmd commandID) {
 		if len(current) == 0 || m.bookmarkIndex < 0 || m.bookmarkIndex >= len(current) {
 			return
 		}
+		if current[m.bookmarkIndex].FromTOC && !m.config.AllowDeleteTOC {
+			m.setStatus(m.tr(i18n.MsgBookmarkTOCLocked))
+			return
+		}
 		name := current[m.bookmarkIndex].Name
 		current = append(current[:m.bookmarkIndex], current[m.bookmarkIndex+1:]...)
 		m.bookmarks[m.currentBook.Book.ID] = current
 		if m.bookmarkIndex >= len(current) && m.bookmarkIndex > 0 {
 			m.bookmarkIndex--
 		}
-		m.setStatus("Deleted bookmark: " + name)
+		m.bookmarkTop = scrollTopFor(m.bookmarkTop, m.bookmarkIndex, m.visibleLineCount())
+		m.setTransientStatus(m.tr(i18n.MsgBookmarkDeleted, name))
+	case cmdRenameBookmark:
+		if !m.bookmarksOpen || m.currentBook == nil {
+			return
+		}
+		current := m.currentBookmarks()
+		if m.bookmarkIndex < 0 || m.bookmarkIndex >= len(current) {
+			return
+		}
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.startInput(cmdRenameBookmark, m.tr(i18n.PromptRenameBookmark))
+		m.setInput(current[m.bookmarkIndex].Name)
+	case cmdStats:
+		m.menuOpen = false
+		m.activeMenu = -1
+		if m.currentBook == nil {
+			m.setStatus(m.tr(i18n.MsgStatsNoBook))
+			return
+		}
+		m.statsOpen = true
+		m.setStatus(m.tr(i18n.MsgStatsHint))
+	case cmdMetadata:
+		m.menuOpen = false
+		m.activeMenu = -1
+		if m.currentBook == nil {
+			m.setStatus(m.tr(i18n.MsgMetadataNoBook))
+			return
+		}
+		m.metadataOpen = true
+		m.setStatus(m.tr(i18n.MsgMetadataHint))
+	case cmdFindPrevious:
+		m.menuOpen = false
+		m.activeMenu = -1
+		if m.lastSearch == "" {
+			m.setStatus(m.tr(i18n.MsgFindEmpty))
+			return
+		}
+		m.performSearch(m.lastSearch, false, true)
+	case cmdAddNote:
+		m.menuOpen = false
+		m.activeMenu = -1
+		if m.currentBook == nil {
+			m.setStatus(m.tr(i18n.MsgNoteNoBook))
+			return
+		}
+		m.notesOpen = false
+		m.startNoteEditor(-1)
+	case cmdNotes:
+		m.menuOpen = false
+		m.activeMenu = -1
+		if m.currentBook == nil {
+			m.setStatus(m.tr(i18n.MsgNotesNoBook))
+			return
+		}
+		if len(m.currentNotes()) == 0 {
+			m.setStatus(m.tr(i18n.MsgNotesEmpty))
+			return
+		}
+		m.notesOpen = true
+		m.noteIndex = 0
+		m.noteTop = 0
+		m.setStatus(m.tr(i18n.MsgNotesHint))
+	case cmdExportBookmarks:
+		if m.currentBook == nil {
+			m.setStatus(m.tr(i18n.MsgBookmarksNoBook))
+			return
+		}
+		if len(m.currentBookmarks()) == 0 {
+			m.setStatus(m.tr(i18n.MsgBookmarksEmpty))
+			return
+		}
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.bookmarksOpen = false
+		m.startInput(cmdExportBookmarks, m.tr(i18n.PromptExportBookmarks))
+		m.setInput(m.defaultExportPath())
 	case cmdRecentFiles:
 		if len(m.recentFiles) == 0 {
-			m.setStatus("Recent files: list is empty.")
+			m.setStatus(m.tr(i18n.MsgRecentEmpty))
 			return
 		}
 		m.recentOpen = true
 		m.recentIndex = 0
+		m.recentTop = 0
+		m.refreshRecentFileMtimes()
 		m.menuOpen = false
 		m.activeMenu = -1
-		m.setStatus("Recent files: Use ↑/↓ to select, Enter to open, Esc to cancel.")
-	case cmdHelp:
-		m.setStatus("Help: not yet implemented (help screen will appear in later phase).")
-	default:
-		return
-	}
-}
-
-// currentBookmarks returns the slice of bookmarks for the currently
-// open book. It never returns nil; when no book is open or there are no
-// bookmarks for the book it returns an empty slice.
-func (m *Model) currentBookmarks() []reader.Bookmark {
-	if m.currentBook == nil {
-		return nil
-	}
-	if m.bookmarks == nil {
-		return nil
-	}
-	list, ok := m.bookmarks[m.currentBook.Book.ID]
-	if !ok {
-		return nil
-	}
-	return list
+		m.setStatus(m.tr(i18n.MsgRecentHint))
+	case cmdToggleWordWrap:
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.wordWrap = !m.wordWrap
+		pos := m.currentPos
+		m.reflowWrappedLines()
+		m.jumpToPosition(pos)
+		if m.wordWrap {
+			m.setStatus(m.tr(i18n.MsgWordWrapOn))
+		} else {
+			m.setStatus(m.tr(i18n.MsgWordWrapOff))
+		}
+	case cmdZoomIn, cmdZoomOut, cmdZoomReset:
+		m.menuOpen = false
+		m.activeMenu = -1
+		switch cmd {
+		case cmdZoomIn:
+			m.zoom = min(m.zoom+1, maxZoom)
+		case cmdZoomOut:
+			m.zoom = max(m.zoom-1, 0)
+		default:
+			m.zoom = 0
+		}
+		pos := m.currentPos
+		m.reflowWrappedLines()
+		m.jumpToPosition(pos)
+		m.setTransientStatus(m.tr(i18n.MsgZoom, m.zoom, m.textColumnWidth(max(0, m.width-2))))
+	case cmdSearchMode:
+		m.searchMode = (m.searchMode + 1) % (SearchRegex + 1)
+		// Cached matches were computed under the previous mode.
+		m.lastSearchOffset = -1
+		m.highlightedMatches = nil
+		m.highlightedLengths = nil
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.setStatus(m.tr(i18n.MsgFindMode, m.tr(m.searchMode.label())))
+	case cmdToggleSearchWhitespace:
+		m.searchNormalizeSpace = !m.searchNormalizeSpace
+		// Cached matches were computed under the previous mode.
+		m.lastSearchOffset = -1
+		m.highlightedMatches = nil
+		m.highlightedLengths = nil
+		if m.searchNormalizeSpace {
+			m.setStatus(m.tr(i18n.MsgFindNormalizeOn))
+		} else {
+			m.setStatus(m.tr(i18n.MsgFindNormalizeOff))
+		}
+	case cmdClearRecentFiles:
+		m.menuOpen = false
+		m.activeMenu = -1
+		if len(m.recentFiles) == 0 {
+			m.setStatus(m.tr(i18n.MsgRecentEmpty))
+			return
+		}
+		m.askConfirm(m.tr(i18n.PromptClearRecent), cmdClearRecentFiles)
+	case cmdGotoPercent:
+		if m.currentBook == nil {
+			m.setStatus(m.tr(i18n.MsgGotoNoBook))
+			return
+		}
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.startInput(cmdGotoPercent, m.tr(i18n.PromptGotoPercent))
+		m.setStatus(m.tr(i18n.MsgGotoHint))
+	case cmdGotoChapter:
+		if m.currentBook == nil {
+			m.setStatus(m.tr(i18n.MsgGotoNoBook))
+			return
+		}
+		m.menuOpen = false
+		m.activeMenu = -1
+		if len(m.currentBook.Book.Chapters) == 0 {
+			m.setStatus(m.tr(i18n.MsgGotoChapterNone))
+			return
+		}
+		m.startInput(cmdGotoChapter, m.tr(i18n.PromptGotoChapter, len(m.currentBook.Book.Chapters)))
+		m.setStatus(m.tr(i18n.MsgGotoChapterHint))
+	case cmdGotoLine:
+		if m.currentBook == nil || len(m.lines) == 0 {
+			m.setStatus(m.tr(i18n.MsgGotoNoBook))
+			return
+		}
+		m.menuOpen = false
+		m.activeMenu = -1
+		m.startInput(cmdGotoLine, m.tr(i18n.PromptGotoLine, len(m.lines)-1))
+		m.setStatus(m.tr(i18n.MsgGotoLineHint))
+	case cmdHelp:
+		m.menuOpen = false
+		m.activeMenu = -1
+		if m.helpOpen {
+			m.helpOpen = false
+			return
+		}
+		m.helpOpen = true
+		m.helpTopLine = 0
+		m.setStatus(m.tr(i18n.MsgHelpHint))
+	default:
+		return
+	}
+}
+
+// askConfirm shows a yes/no question; cmd is passed to runConfirmed if
+// the user answers "y".
+func (m *Model) askConfirm(prompt string, cmd commandID) {
+	m.confirmOpen = true
+	m.confirmPrompt = prompt
+	m.confirmCommand = cmd
+	m.setStatus(prompt)
+}
+
+// handleConfirmKey answers the pending question: "y" confirms, any
+// other key cancels (the default, as the [y/N] hint suggests).
+func (m *Model) handleConfirmKey(msg tea.KeyMsg) {
+	cmd := m.confirmCommand
+	m.confirmOpen = false
+	m.confirmPrompt = ""
+	m.confirmCommand = cmdNone
+	if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && (msg.Runes[0] == 'y' || msg.Runes[0] == 'Y') {
+		m.runConfirmed(cmd)
+		return
+	}
+	m.setStatus(m.tr(i18n.MsgCancelled))
+}
+
+// runConfirmed performs the destructive part of a command after the
+// user confirmed it.
+func (m *Model) runConfirmed(cmd commandID) {
+	switch cmd {
+	case cmdClearRecentFiles:
+		m.recentFiles = nil
+		m.recentFileMtimes = nil
+		m.recentOpen = false
+		m.recentIndex = 0
+		m.setStatus(m.tr(i18n.MsgRecentCleared))
+	}
+}
+
+// renameBookmark gives the bookmark selected in the bookmarks dialog a
+// new name. The change is persisted with the other bookmarks on exit.
+func (m *Model) renameBookmark(name string) {
+	current := m.currentBookmarks()
+	if m.currentBook == nil || m.bookmarkIndex < 0 || m.bookmarkIndex >= len(current) {
+		return
+	}
+	if name == "" {
+		m.setStatus(m.tr(i18n.MsgBookmarkNameEmpty))
+		return
+	}
+	current[m.bookmarkIndex].Name = name
+	m.setTransientStatus(m.tr(i18n.MsgBookmarkRenamed, name))
+}
+
+// currentBookmarks returns the slice of bookmarks for the currently
+// open book. It never returns nil; when no book is open or there are no
+// bookmarks for the book it returns an empty slice.
+func (m *Model) currentBookmarks() []reader.Bookmark {
+	if m.currentBook == nil {
+		return nil
+	}
+	if m.bookmarks == nil {
+		return nil
+	}
+	list, ok := m.bookmarks[m.currentBook.Book.ID]
+	if !ok {
+		return nil
+	}
+	return list
 }
 
 func (m *Model) setStatus(text string) {
 	m.statusLine = text
 	m.statusDirty = true
+	m.statusExpiry = time.Time{}
+}
+
+// statusNotificationDuration is how long a transient status message
+// stays visible.
+const statusNotificationDuration = 3 * time.Second
+
+// setTransientStatus shows a notification that reverts to the default
+// hint after statusNotificationDuration unless replaced earlier.
+func (m *Model) setTransientStatus(text string) {
+	m.setStatus(text)
+	m.statusExpiry = time.Now().Add(statusNotificationDuration)
+	m.queueCmd(tea.Tick(statusNotificationDuration, func(time.Time) tea.Msg {
+		return statusExpiredMsg{}
+	}))
+}
+
+// CurrentBook returns the open book, or nil if none is open. Callers
+// must treat the result as read-only.
+func (m Model) CurrentBook() *reader.LoadedBook {
+	return m.currentBook
+}
+
+// ExportRecentFiles returns a copy of the recent files list, most
+// recent first, so callers (e.g. main) can persist it.
+func (m Model) ExportRecentFiles() []string {
+	out := make([]string, len(m.recentFiles))
+	copy(out, m.recentFiles)
+	return out
+}
+
+// SetRecentFiles installs the persisted recent files list, most recent
+// first, trimmed to recentLimit. Books already opened in this session,
+// e.g. from the command line, stay at the front.
+func (m *Model) SetRecentFiles(paths []string) {
+	opened := m.recentFiles
+	m.recentFiles = nil
+	for _, path := range slices.Backward(paths) {
+		m.addRecentFile(path)
+	}
+	for _, path := range slices.Backward(opened) {
+		m.addRecentFile(path)
+	}
+}
+
+// SetUnifiedReader makes the model load books with u, e.g. a reader
+// with plugins registered, instead of reader.NewDefaultUnifiedReader.
+func (m *Model) SetUnifiedReader(u reader.UnifiedReader) {
+	m.unifiedReader = u
+}
+
+// applyConfig copies every config-derived setting into the model. It is
+// the single place where config.Config fields map onto UI state;
+// non-positive sizes keep the model's current values.
+func (m *Model) applyConfig(cfg config.Config) {
+	m.config = cfg
+	if cfg.RecentListSize > 0 {
+		m.recentLimit = cfg.RecentListSize
+		if len(m.recentFiles) > m.recentLimit {
+			m.recentFiles = m.recentFiles[:m.recentLimit]
+		}
+	}
+	if cfg.InputHistorySize > 0 {
+		m.inputHistorySize = cfg.InputHistorySize
+	}
+	if cfg.ScrollLines > 0 {
+		m.scrollLines = cfg.ScrollLines
+	}
+	if cfg.TabWidth > 0 {
+		m.tabWidth = cfg.TabWidth
+	}
+	m.leftMargin = max(0, cfg.LeftMargin)
+	m.rightMargin = max(0, cfg.RightMargin)
+	if cfg.AutoScrollDelay > 0 {
+		m.autoScrollDelay = time.Duration(cfg.AutoScrollDelay) * time.Millisecond
+	}
+	m.tabCompletionEnabled = cfg.TabCompletionEnabled
+	m.gKeyJumpsToEnd = cfg.GKey == config.GKeyEnd
+	m.wordWrap = cfg.WordWrap
+	m.defaultLibraryPath = cfg.DefaultLibraryPath
+	if cfg.BookshelfScanDepth >= 0 {
+		m.bookshelfScanDepth = cfg.BookshelfScanDepth
+	}
+	m.themeName = cfg.ThemeOverride
+	m.autoTheme = cfg.AutoTheme
+	m.applyTheme()
+	m.printer = i18n.NewPrinter(cfg.Language)
+	m.menus = buildMenus(defaultMenuSpec, m.printer)
+	m.keyBindings = keyBindingsWithOverrides(cfg.Keybindings, m.gKeyJumpsToEnd)
 }
 
-// SetRecentLimit updates the maximum number of recent files remembered
-// in memory. Non-positive values are ignored.
-func (m *Model) SetRecentLimit(limit int) {
-	if limit <= 0 {
+// applyTheme resolves the theme from themeName and, when autoTheme is
+// set, the detected colorScheme.
+func (m *Model) applyTheme() {
+	scheme := ""
+	if m.autoTheme {
+		scheme = m.colorScheme
+	}
+	m.theme = themeByName(m.themeName, scheme)
+}
+
+// DetectColorScheme queries the terminal background and adapts the
+// theme to it when Config.AutoTheme is set. The constructors leave this
+// out so that building a model does no terminal I/O; call it before
+// the Bubble Tea program starts reading input.
+func (m *Model) DetectColorScheme() {
+	if !m.autoTheme {
 		return
 	}
-	m.recentLimit = limit
+	m.colorScheme = detectColorScheme()
+	m.applyTheme()
+}
+
+// tr formats a message key from package i18n in the configured
+// language. Models built without a config fall back to English.
+func (m Model) tr(key string, args ...any) string {
+	p := m.printer
+	if p == nil {
+		p = i18n.NewPrinter("")
+	}
+	return p.Sprintf(key, args...)
+}
+
+// ConfigReloadMsg asks a running model to re-apply configuration, e.g.
+// after the user edited the config file and sent SIGHUP. When Err is
+// set the reload failed and the current settings are kept.
+type ConfigReloadMsg struct {
+	Config config.Config
+	Err    error
+}
+
+// ExportPositions returns the last reading position of every book seen,
+// including the open one, so callers (e.g. main) can persist them.
+func (m Model) ExportPositions() map[reader.BookID]reader.Position {
+	out := make(map[reader.BookID]reader.Position, len(m.positions)+1)
+	for k, v := range m.positions {
+		out[k] = v
+	}
+	if m.currentBook != nil && m.currentBook.Book.ID != "" {
+		out[m.currentBook.Book.ID] = m.currentPos
+	}
+	return out
 }
 
 // ExportBookmarks returns a copy of the in-memory bookmarks map so that
@@ -697,15 +1784,71 @@ func (m Model) ExportBookmarks() map[reader.BookID][]reader.Bookmark {
 // setBook installs a newly loaded book into the model and prepares a
 // wrapped view over its text based on the current viewport width.
 func (m *Model) setBook(book reader.LoadedBook) {
+	m.rememberPosition()
+	if err := reader.ValidateLoadedBook(book); err != nil {
+		// Bubble Tea owns the terminal; stderr would garble the screen.
+		m.setStatus(m.tr(i18n.MsgBookInvalid, book.Book.Title, err))
+	}
 	m.currentBook = &book
 	m.textRunes = []rune(book.Text)
+	m.isRTL = detectRTL(m.textRunes)
+	// Loaders may leave the aggregate unset; derive it so that progress
+	// percentages work for every format.
+	if m.currentBook.Book.TotalCharacters == 0 {
+		m.currentBook.Book.TotalCharacters = len(m.textRunes)
+	}
+	if m.currentBook.Stats == (reader.BookStats{}) {
+		m.currentBook.Stats = reader.ComputeStats(book)
+	}
 	m.topLine = 0
 	m.currentPos = reader.Position{ChapterIndex: 0, OffsetInChapter: 0}
 	m.lastSearch = ""
+	m.searchNoMatch = false
 	m.lastSearchOffset = -1
+	m.highlightedMatches = nil
+	m.highlightedLengths = nil
 	m.tocIndex = 0
+	m.tocTop = 0
+	m.selecting = false
+	m.cursorMode = false
+	m.autoScroll = false
+	m.lookupWord = ""
+	m.popupOpen = false
+	m.readingCPM = 0
 	m.reflowWrappedLines()
 	m.updateCurrentPositionFromTopLine()
+	m.startReadingSession()
+	m.addToLibrary()
+	m.addTOCBookmarks()
+}
+
+// rememberPosition records the reading position of the open book in
+// positions.
+func (m *Model) rememberPosition() {
+	if m.currentBook == nil || m.currentBook.Book.ID == "" {
+		return
+	}
+	if m.positions == nil {
+		m.positions = make(map[reader.BookID]reader.Position)
+	}
+	m.positions[m.currentBook.Book.ID] = m.currentPos
+	m.updateLibraryProgress()
+}
+
+// restoreSavedPosition moves to the remembered position of the open
+// book, if any. It reports whether the position had to be clamped
+// because the book changed since it was saved.
+func (m *Model) restoreSavedPosition() bool {
+	if m.currentBook == nil {
+		return false
+	}
+	saved, ok := m.positions[m.currentBook.Book.ID]
+	if !ok || m.currentBook.Book.ID == "" {
+		return false
+	}
+	pos := m.clampPosition(saved)
+	m.jumpToPosition(pos)
+	return pos != saved
 }
 
 // openPath attempts to load the given file via the unified reader and
@@ -713,18 +1856,394 @@ func (m *Model) setBook(book reader.LoadedBook) {
 func (m *Model) openPath(path string) {
 	path = strings.TrimSpace(path)
 	if path == "" {
-		m.setStatus("No file path provided.")
+		m.setStatus(m.tr(i18n.MsgNoPath))
+		return
+	}
+	m.reloadPos = nil
+	if reader.IsURL(path) {
+		m.startLoading(path, false)
+		return
+	}
+
+	// Resolve symlinks so that the same physical book is not listed
+	// twice in recent files under different names. If resolution fails
+	// (e.g. the file does not exist) the original path is kept and the
+	// reader reports the error.
+	resolved := path
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		resolved = real
+	}
+
+	// Plugin formats are unknown to the content sniffing.
+	if !m.unifiedReader.OpensWithPlugin(resolved) && !looksLikeBook(resolved) {
+		m.setStatus(m.tr(i18n.MsgUnsupportedFile))
 		return
 	}
 
-	book, err := m.unifiedReader.Open(path)
+	m.startLoading(resolved, resolved != filepath.Clean(path))
+}
+
+// startLoading parses the book at path, a file or a URL, in the
+// background so large books and downloads do not freeze the UI; the
+// result arrives as a bookLoadedMsg.
+func (m *Model) startLoading(path string, viaSymlink bool) {
+	m.loadingInProgress = true
+	m.loadingPath = path
+	m.spinnerFrame = 0
+	m.setStatus(m.tr(i18n.LabelLoading, path))
+	unified := m.unifiedReader
+	newTab := m.newTabPending && m.currentBook != nil
+	m.newTabPending = false
+	m.queueCmd(func() tea.Msg {
+		book, err := unified.Open(path)
+		return bookLoadedMsg{path: path, viaSymlink: viaSymlink, newTab: newTab, book: book, err: err}
+	})
+	m.queueCmd(spinnerTick())
+}
+
+// bookLoadedMsg carries the result of an asynchronous openPath.
+type bookLoadedMsg struct {
+	path       string
+	viaSymlink bool
+	newTab     bool // show the book in a new tab
+	book       reader.LoadedBook
+	err        error
+}
+
+// escSequenceWindow is how soon after Esc a key must arrive to be read
+// as an Alt combination.
+const escSequenceWindow = 50 * time.Millisecond
+
+// escTimeoutMsg ends the Esc-prefix window started by an Esc key press.
+type escTimeoutMsg struct{}
+
+// statusExpiredMsg asks the model to drop an expired transient status.
+type statusExpiredMsg struct{}
+
+// spinnerTickMsg advances the loading spinner.
+type spinnerTickMsg struct{}
+
+// spinnerFrames are the animation frames of the loading spinner.
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+func spinnerTick() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg {
+		return spinnerTickMsg{}
+	})
+}
+
+// queueCmd schedules a command to be returned from the current Update
+// call. Helpers that run deep inside key handling use it instead of
+// threading tea.Cmd values through every return path.
+func (m *Model) queueCmd(cmd tea.Cmd) {
+	if cmd != nil {
+		m.cmds = append(m.cmds, cmd)
+	}
+}
+
+// takeCmds returns the queued commands as a single command and clears
+// the queue.
+func (m *Model) takeCmds() tea.Cmd {
+	cmds := m.cmds
+	m.cmds = nil
+	switch len(cmds) {
+	case 0:
+		return nil
+	case 1:
+		return cmds[0]
+	}
+	return tea.Batch(cmds...)
+}
+
+// looksLikeBook sniffs the first 512 bytes of the file to reject
+// binaries (images, executables, ...) before handing them to a format
+// parser, which would otherwise fail with a cryptic error. Files that
+// cannot be read are let through so the reader reports the I/O error.
+func looksLikeBook(path string) bool {
+	f, err := os.Open(path)
 	if err != nil {
-		m.setStatus("Failed to open: " + err.Error())
+		return true
+	}
+	defer f.Close()
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return true
+	}
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(buf[:n]), ";")
+	switch {
+	case reader.SniffExtension(buf[:n]) != "":
+		// MOBI and AZW3 files are sniffed as application/octet-stream.
+		return true
+	case strings.HasPrefix(contentType, "text/"):
+		return true
+	case contentType == "application/epub+zip", contentType == "application/xml":
+		return true
+	case contentType == "application/zip":
+		// EPUB containers are sniffed as plain ZIP archives.
+		return true
+	}
+	return false
+}
+
+// refreshRecentFileMtimes stats every recent file so the dialog can
+// show modification dates and flag files that have gone missing.
+func (m *Model) refreshRecentFileMtimes() {
+	m.recentFileMtimes = make([]time.Time, len(m.recentFiles))
+	for i, path := range m.recentFiles {
+		if info, err := os.Stat(path); err == nil {
+			m.recentFileMtimes[i] = info.ModTime()
+		}
+	}
+}
+
+// removeRecentFile drops the entry at idx from the recent files list
+// (the file on disk is left untouched) and keeps the dialog selection
+// in range, closing the dialog once the list is empty.
+func (m *Model) removeRecentFile(idx int) {
+	if idx < 0 || idx >= len(m.recentFiles) {
+		return
+	}
+	path := m.recentFiles[idx]
+	m.recentFiles = append(m.recentFiles[:idx], m.recentFiles[idx+1:]...)
+	if idx < len(m.recentFileMtimes) {
+		m.recentFileMtimes = append(m.recentFileMtimes[:idx], m.recentFileMtimes[idx+1:]...)
+	}
+	if len(m.recentFiles) == 0 {
+		m.recentOpen = false
+		m.recentIndex = 0
+		m.setStatus(m.tr(i18n.MsgRecentRemovedLast, path))
 		return
 	}
+	if m.recentIndex >= len(m.recentFiles) {
+		m.recentIndex = len(m.recentFiles) - 1
+	}
+	m.recentTop = scrollTopFor(m.recentTop, m.recentIndex, m.visibleLineCount())
+	m.setStatus(m.tr(i18n.MsgRecentRemoved, path))
+}
+
+// addOpenedFile records an opened book in the recent files and, unless
+// it was downloaded, starts the next Open prompt in its directory.
+func (m *Model) addOpenedFile(path string) {
+	m.addRecentFile(path)
+	if !reader.IsURL(path) {
+		m.lastOpenDir = filepath.Dir(path)
+	}
+}
+
+// addRecentFile moves path to the front of the recent files list,
+// dropping any earlier occurrence and trimming the list to recentLimit.
+func (m *Model) addRecentFile(path string) {
+	list := make([]string, 0, len(m.recentFiles)+1)
+	list = append(list, path)
+	for _, p := range m.recentFiles {
+		if p != path {
+			list = append(list, p)
+		}
+	}
+	if m.recentLimit > 0 && len(list) > m.recentLimit {
+		list = list[:m.recentLimit]
+	}
+	m.recentFiles = list
+}
 
-	m.setBook(book)
-	m.setStatus("Opened: " + book.Book.Title)
+// expandPath resolves a leading "~" to the user's home directory and
+// substitutes $VAR and ${VAR} references from the environment, matching
+// what users expect from typing a path in a shell. It is meant for
+// typed or configured paths only: applied to a real file name it would
+// mangle names containing "$".
+func expandPath(s string) string {
+	prefix := ""
+	if s == "~" || strings.HasPrefix(s, "~/") || strings.HasPrefix(s, "~"+string(filepath.Separator)) {
+		if home, err := os.UserHomeDir(); err == nil {
+			prefix = home
+			s = s[1:]
+		}
+	}
+	return prefix + os.ExpandEnv(s)
+}
+
+// startOpenPrompt asks for a book path as a line of text, pre-filled
+// with dir (or the last used or library directory if dir is empty).
+func (m *Model) startOpenPrompt(dir string) {
+	m.startInput(cmdOpen, m.tr(i18n.PromptOpen))
+	switch {
+	case dir != "":
+		m.setInput(withTrailingSeparator(dir))
+	case m.lastOpenDir != "":
+		m.setInput(withTrailingSeparator(m.lastOpenDir))
+	case m.defaultLibraryPath != "":
+		m.setInput(withTrailingSeparator(m.defaultLibraryPath))
+	}
+	m.setStatus(m.tr(i18n.MsgOpenHint))
+}
+
+// handleBrowserKey handles keys while the Open dialog's file browser is
+// shown.
+func (m *Model) handleBrowserKey(msg tea.KeyMsg) bool {
+	rows := m.dialogListRows() - 1
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.browserOpen = false
+	case tea.KeyUp:
+		m.browser.move(-1, rows)
+	case tea.KeyDown:
+		m.browser.move(1, rows)
+	case tea.KeyPgUp:
+		m.browser.move(-max(1, rows), rows)
+	case tea.KeyPgDown:
+		m.browser.move(max(1, rows), rows)
+	case tea.KeyHome:
+		m.browser.move(-len(m.browser.entries), rows)
+	case tea.KeyEnd:
+		m.browser.move(len(m.browser.entries), rows)
+	case tea.KeyBackspace:
+		if err := m.browser.up(); err != nil {
+			m.setStatus(m.tr(i18n.MsgBrowserFailed, err))
+		}
+		m.browser.move(0, rows)
+	case tea.KeyTab:
+		m.browserOpen = false
+		m.startOpenPrompt(m.browser.dir)
+	case tea.KeyEnter:
+		entry, ok := m.browser.current()
+		if !ok {
+			return true
+		}
+		if entry.isDir {
+			if err := m.browser.chdir(entry.path); err != nil {
+				m.setStatus(m.tr(i18n.MsgBrowserFailed, err))
+			}
+			return true
+		}
+		m.browserOpen = false
+		m.openPath(entry.path)
+	default:
+		return false
+	}
+	return true
+}
+
+// startInput enters line-input mode with an empty buffer; the input is
+// dispatched to cmd when confirmed.
+func (m *Model) startInput(cmd commandID, prompt string) {
+	m.inputMode = true
+	m.inputPrompt = prompt
+	m.inputBuffer = m.inputBuffer[:0]
+	m.inputCursor = 0
+	m.pendingCommand = cmd
+	m.inputHistoryPos = -1
+}
+
+// setInput replaces the input line and moves the cursor to its end.
+func (m *Model) setInput(s string) {
+	m.inputBuffer = []rune(s)
+	m.inputCursor = len(m.inputBuffer)
+}
+
+// rememberInput appends a confirmed input to the command's history,
+// moving repeated entries to the end and trimming to inputHistorySize.
+func (m *Model) rememberInput(cmd commandID, input string) {
+	if input == "" || m.inputHistorySize <= 0 {
+		return
+	}
+	if m.inputHistory == nil {
+		m.inputHistory = make(map[commandID][]string)
+	}
+	list := make([]string, 0, len(m.inputHistory[cmd])+1)
+	for _, h := range m.inputHistory[cmd] {
+		if h != input {
+			list = append(list, h)
+		}
+	}
+	list = append(list, input)
+	if len(list) > m.inputHistorySize {
+		list = list[len(list)-m.inputHistorySize:]
+	}
+	m.inputHistory[cmd] = list
+}
+
+// recallInput moves through the pending command's input history; delta
+// is -1 for older entries (Up) and +1 for newer ones (Down). Moving past
+// the newest entry returns to an empty line.
+func (m *Model) recallInput(delta int) {
+	history := m.inputHistory[m.pendingCommand]
+	if len(history) == 0 {
+		return
+	}
+	pos := m.inputHistoryPos
+	switch {
+	case pos == -1 && delta < 0:
+		pos = len(history) - 1
+	case pos == -1:
+		return
+	default:
+		pos += delta
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= len(history) {
+		m.inputHistoryPos = -1
+		m.setInput("")
+		return
+	}
+	m.inputHistoryPos = pos
+	m.setInput(history[pos])
+}
+
+// withTrailingSeparator returns dir ending in exactly one path
+// separator, ready for a file name to be appended.
+func withTrailingSeparator(dir string) string {
+	if strings.HasSuffix(dir, string(filepath.Separator)) {
+		return dir
+	}
+	return dir + string(filepath.Separator)
+}
+
+// completePath extends input to the longest common prefix of the
+// directory entries it matches. Directories get a trailing separator so
+// completion can continue into them. The typed directory part is kept
+// as-is (e.g. a leading "~" is not expanded in the result).
+func completePath(input string) string {
+	dir, base := filepath.Split(input)
+	listDir := expandPath(dir)
+	if listDir == "" {
+		listDir = "."
+	}
+	entries, err := os.ReadDir(listDir)
+	if err != nil {
+		return input
+	}
+
+	var common []rune
+	matched := false
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, base) {
+			continue
+		}
+		if e.IsDir() {
+			name += string(filepath.Separator)
+		}
+		if !matched {
+			common = []rune(name)
+			matched = true
+			continue
+		}
+		nameRunes := []rune(name)
+		n := 0
+		for n < len(common) && n < len(nameRunes) && common[n] == nameRunes[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	if !matched {
+		return input
+	}
+	return dir + string(common)
 }
 
 // handleInputKey processes key presses while the model is in a simple
@@ -734,6 +2253,7 @@ func (m *Model) handleInputKey(msg tea.KeyMsg) bool {
 	case tea.KeyEsc:
 		m.inputMode = false
 		m.inputBuffer = nil
+		m.inputCursor = 0
 		m.pendingCommand = cmdNone
 		return true
 	case tea.KeyEnter:
@@ -741,70 +2261,330 @@ func (m *Model) handleInputKey(msg tea.KeyMsg) bool {
 		pending := m.pendingCommand
 		m.inputMode = false
 		m.inputBuffer = nil
+		m.inputCursor = 0
 		m.pendingCommand = cmdNone
+		m.rememberInput(pending, input)
 
 		if pending == cmdOpen {
+			// Only typed paths get shell-style expansion; real file
+			// names may contain "$" or start with "~".
+			if !reader.IsURL(input) {
+				input = expandPath(input)
+			}
 			m.openPath(input)
 		} else if pending == cmdFind {
-			m.performSearch(input, true)
+			m.performSearch(input, true, false)
+		} else if pending == cmdGotoPercent {
+			m.gotoPercent(input)
+		} else if pending == cmdGotoChapter {
+			m.gotoChapter(input)
+		} else if pending == cmdGotoLine {
+			m.gotoLine(input)
+		} else if pending == cmdRenameBookmark {
+			m.renameBookmark(input)
+		} else if pending == cmdExportBookmarks {
+			m.exportBookmarks(input)
+		} else if pending == cmdExportText {
+			m.exportText(input)
+		} else if pending == cmdConfig {
+			m.setConfigValue(input)
 		}
 		return true
 	case tea.KeyBackspace:
-		if len(m.inputBuffer) > 0 {
-			m.inputBuffer = m.inputBuffer[:len(m.inputBuffer)-1]
+		if m.inputCursor > 0 {
+			m.inputBuffer = slices.Delete(m.inputBuffer, m.inputCursor-1, m.inputCursor)
+			m.inputCursor--
+		}
+		return true
+	case tea.KeyDelete:
+		if m.inputCursor < len(m.inputBuffer) {
+			m.inputBuffer = slices.Delete(m.inputBuffer, m.inputCursor, m.inputCursor+1)
+		}
+		return true
+	case tea.KeyLeft:
+		if m.inputCursor > 0 {
+			m.inputCursor--
+		}
+		return true
+	case tea.KeyRight:
+		if m.inputCursor < len(m.inputBuffer) {
+			m.inputCursor++
+		}
+		return true
+	case tea.KeyHome:
+		m.inputCursor = 0
+		return true
+	case tea.KeyEnd:
+		m.inputCursor = len(m.inputBuffer)
+		return true
+	case tea.KeyUp:
+		m.recallInput(-1)
+		return true
+	case tea.KeyDown:
+		m.recallInput(1)
+		return true
+	case tea.KeyTab:
+		if m.tabCompletionEnabled && m.pendingCommand == cmdOpen {
+			m.setInput(completePath(string(m.inputBuffer)))
 		}
 		return true
 	default:
+		if (m.pendingCommand == cmdGotoChapter || m.pendingCommand == cmdGotoLine) && strings.Trim(string(msg.Runes), "0123456789") != "" {
+			// The chapter and line prompts only take a number.
+			return true
+		}
 		if len(msg.Runes) > 0 {
-			m.inputBuffer = append(m.inputBuffer, msg.Runes...)
+			m.inputBuffer = slices.Insert(m.inputBuffer, m.inputCursor, msg.Runes...)
+			m.inputCursor += len(msg.Runes)
 			return true
 		}
 	}
 
-	return false
+	return false
+}
+
+// performSearch executes a simple substring search over the book text.
+// When newTerm is true, the previous search state is reset; otherwise,
+// the search continues from the last match position, forward or, when
+// backward is set, backward. On success it jumps the viewport to the
+// found position and reports which of the matches it is; on failure it
+// updates the status bar with an explanatory message.
+func (m *Model) performSearch(term string, newTerm, backward bool) {
+	if m.currentBook == nil || len(term) == 0 {
+		m.setStatus(m.tr(i18n.MsgFindEmpty))
+		return
+	}
+
+	if newTerm || term != m.lastSearch {
+		m.lastSearch = term
+		m.lastSearchOffset = -1
+		m.highlightedMatches = nil
+		m.highlightedLengths = nil
+	}
+	if m.highlightedMatches == nil {
+		matches, lengths, err := findAllMatches(m.textRunes, term, m.searchNormalizeSpace, m.searchMode)
+		if err != nil {
+			m.setStatus(m.tr(i18n.MsgFindBadRegex, err))
+			return
+		}
+		m.highlightedMatches, m.highlightedLengths = matches, lengths
+	}
+
+	if len(m.highlightedMatches) == 0 {
+		m.searchNoMatch = true
+		m.setStatus(m.tr(i18n.MsgFindNoMatches))
+		return
+	}
+
+	// Matches are sorted, so the next one is the first past the last
+	// reported offset and the previous one the last before it. Without
+	// a reported match, searching backward starts at the end.
+	next := sort.SearchInts(m.highlightedMatches, m.lastSearchOffset+1)
+	if backward {
+		next = sort.SearchInts(m.highlightedMatches, m.lastSearchOffset) - 1
+		if m.lastSearchOffset == -1 {
+			next = len(m.highlightedMatches) - 1
+		}
+	}
+	if next < 0 {
+		m.setStatus(m.tr(i18n.MsgFindNoEarlier))
+		return
+	}
+	if next >= len(m.highlightedMatches) {
+		m.setStatus(m.tr(i18n.MsgFindNoMore))
+		return
+	}
+
+	matchOffset := m.highlightedMatches[next]
+	m.lastSearchOffset = matchOffset
+	pos := m.absoluteOffsetToPosition(matchOffset)
+	m.jumpToPosition(pos)
+	m.setStatus(m.tr(i18n.MsgFindMatch, next+1, len(m.highlightedMatches)))
+}
+
+// findAllMatches returns the rune offsets and rune lengths of every
+// (possibly overlapping) occurrence of term in text, in ascending
+// order of offset. When normalizeSpace is true, whitespace runs in the
+// text (and, for literal modes, in the term) are collapsed to a single
+// space before matching and the results are mapped back to offsets in
+// the original text. In SearchRegex mode matches do not overlap and
+// empty matches are skipped; an invalid expression is returned as an
+// error. The offsets slice is never nil so that callers can distinguish
+// "searched, no matches" from "not searched yet".
+func findAllMatches(text []rune, term string, normalizeSpace bool, mode SearchMode) ([]int, []int, error) {
+	offsets, lengths := []int{}, []int{}
+
+	// origIdx maps each rune of the searched text back to its offset in
+	// text; it stays nil when searching the text as-is.
+	var origIdx []int
+	if normalizeSpace {
+		text, origIdx = collapseWhitespace(text)
+		if mode != SearchRegex {
+			term = string(collapseWhitespaceRunes([]rune(term)))
+		}
+	}
+	if mode == SearchCaseInsensitive {
+		// Folding rune by rune keeps offsets aligned with text.
+		text = foldCase(text)
+		term = string(foldCase([]rune(term)))
+	}
+
+	var spans [][2]int
+	if mode == SearchRegex {
+		re, err := regexp.Compile(term)
+		if err != nil {
+			return offsets, lengths, err
+		}
+		spans = regexpSpans(string(text), re)
+	} else {
+		spans = substringSpans(string(text), term)
+	}
+
+	for _, sp := range spans {
+		if origIdx == nil {
+			offsets = append(offsets, sp[0])
+			lengths = append(lengths, sp[1]-sp[0])
+			continue
+		}
+		start := origIdx[sp[0]]
+		end := origIdx[sp[1]-1] + 1
+		offsets = append(offsets, start)
+		lengths = append(lengths, end-start)
+	}
+	return offsets, lengths, nil
+}
+
+// substringSpans returns the rune start and end of every (possibly
+// overlapping) occurrence of term in s.
+func substringSpans(s, term string) [][2]int {
+	termLen := utf8.RuneCountInString(term)
+	if termLen == 0 {
+		return nil
+	}
+	var spans [][2]int
+	byteOff, runeOff := 0, 0
+	for {
+		idx := strings.Index(s[byteOff:], term)
+		if idx == -1 {
+			return spans
+		}
+		runeOff += utf8.RuneCountInString(s[byteOff : byteOff+idx])
+		spans = append(spans, [2]int{runeOff, runeOff + termLen})
+
+		// Resume one rune past the match start to allow overlaps.
+		_, size := utf8.DecodeRuneInString(s[byteOff+idx:])
+		byteOff += idx + size
+		runeOff++
+	}
+}
+
+// regexpSpans returns the rune start and end of every non-empty match
+// of re in s.
+func regexpSpans(s string, re *regexp.Regexp) [][2]int {
+	var spans [][2]int
+	byteOff, runeOff := 0, 0
+	for _, loc := range re.FindAllStringIndex(s, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		runeOff += utf8.RuneCountInString(s[byteOff:loc[0]])
+		runeLen := utf8.RuneCountInString(s[loc[0]:loc[1]])
+		spans = append(spans, [2]int{runeOff, runeOff + runeLen})
+		byteOff = loc[0]
+	}
+	return spans
 }
 
-// performSearch executes a simple forward substring search over the
-// book text. When newTerm is true, the previous search state is
-// reset; otherwise, the search continues from the last match
-// position. On success it jumps the viewport to the found position;
-// on failure it updates the status bar with an explanatory message.
-func (m *Model) performSearch(term string, newTerm bool) {
-	if m.currentBook == nil || len(term) == 0 {
-		m.setStatus("Find: empty search term.")
-		return
+// foldCase returns a lower-cased copy of text. It maps rune by rune so
+// the result has the same length as text.
+func foldCase(text []rune) []rune {
+	out := make([]rune, len(text))
+	for i, r := range text {
+		out[i] = unicode.ToLower(r)
 	}
+	return out
+}
 
-	text := string(m.textRunes)
-	if newTerm || term != m.lastSearch {
-		m.lastSearch = term
-		m.lastSearchOffset = -1
+// collapseWhitespace replaces every run of whitespace in text with a
+// single space. It also returns, for each rune of the result, the
+// offset of the rune in text it was derived from; a collapsed run maps
+// to its first whitespace rune.
+func collapseWhitespace(text []rune) ([]rune, []int) {
+	out := make([]rune, 0, len(text))
+	origIdx := make([]int, 0, len(text))
+	inSpace := false
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if inSpace {
+				continue
+			}
+			inSpace = true
+			r = ' '
+		} else {
+			inSpace = false
+		}
+		out = append(out, r)
+		origIdx = append(origIdx, i)
 	}
+	return out, origIdx
+}
+
+// collapseWhitespaceRunes is collapseWhitespace without the offset map.
+func collapseWhitespaceRunes(text []rune) []rune {
+	out, _ := collapseWhitespace(text)
+	return out
+}
 
-	start := m.lastSearchOffset + 1
-	if start < 0 {
-		start = 0
+// highlightSearchMatches wraps every search match that intersects the
+// given visual line in the theme's highlight style; the match at
+// lastSearchOffset gets the search highlight instead. line must be the
+// already padded rendering of m.lines[lineIdx]; indexMap, if not nil,
+// maps rune indexes of m.lines[lineIdx] to rune indexes of line, as
+// returned by expandTabs.
+func (m Model) highlightSearchMatches(line string, lineIdx int, indexMap []int) string {
+	if len(m.highlightedMatches) == 0 || lineIdx < 0 || lineIdx >= len(m.lineOffsets) {
+		return line
 	}
-	if start >= len(text) {
-		m.setStatus("Find: no more matches.")
-		return
+	lineStart := m.lineOffsets[lineIdx]
+	lineLen := utf8.RuneCountInString(m.lines[lineIdx])
+	lineEnd := lineStart + lineLen
+	runes := []rune(line)
+	toDisplay := func(i int) int {
+		if indexMap != nil {
+			i = indexMap[i]
+		}
+		return min(i, len(runes))
 	}
 
-	idx := strings.Index(text[start:], term)
-	if idx == -1 {
-		if m.lastSearchOffset == -1 {
-			m.setStatus("Find: no matches.")
+	// Start with the first match at or after the line start, then step
+	// back over earlier matches that still reach into this line.
+	first := sort.SearchInts(m.highlightedMatches, lineStart)
+	for first > 0 && m.highlightedMatches[first-1]+m.highlightedLengths[first-1] > lineStart {
+		first--
+	}
+
+	var b strings.Builder
+	pos := 0
+	for i := first; i < len(m.highlightedMatches); i++ {
+		start := m.highlightedMatches[i]
+		if start >= lineEnd {
+			break
+		}
+		from := max(toDisplay(max(start-lineStart, 0)), pos)
+		to := toDisplay(min(start+m.highlightedLengths[i]-lineStart, lineLen))
+		if from >= to {
+			continue
+		}
+		b.WriteString(string(runes[pos:from]))
+		if start == m.lastSearchOffset {
+			b.WriteString(m.theme.applySearchHighlight(string(runes[from:to])))
 		} else {
-			m.setStatus("Find: no more matches.")
+			b.WriteString(m.theme.applyHighlight(string(runes[from:to])))
 		}
-		return
+		pos = to
 	}
-
-	matchOffset := start + idx
-	m.lastSearchOffset = matchOffset
-	pos := m.absoluteOffsetToPosition(matchOffset)
-	m.jumpToPosition(pos)
-	m.setStatus("Find: match found.")
+	b.WriteString(string(runes[pos:]))
+	return b.String()
 }
 
 // reflowWrappedLines recomputes wrapped lines and their rune offsets
@@ -817,80 +2597,352 @@ func (m *Model) reflowWrappedLines() {
 		return
 	}
 
-	innerWidth := max(0, m.width-2)
-	if innerWidth <= 0 {
+	// The notes gutter can take all of a very narrow main area.
+	textWidth := m.textColumnWidth(max(0, m.width-2))
+	if textWidth <= 0 {
 		m.lines = nil
 		m.lineOffsets = nil
 		m.topLine = 0
 		return
 	}
 
-	lines := make([]string, 0, len(m.textRunes)/innerWidth+1)
-	offsets := make([]int, 0, len(lines))
+	lines, offsets := wrapText(m.textRunes, textWidth, m.wordWrap, m.tabWidth)
+	m.lines = lines
+	m.lineOffsets = offsets
+	if m.topLine >= len(m.lines) {
+		m.topLine = max(0, len(m.lines)-1)
+	}
+}
+
+// maxZoom is the highest zoom level, and minTextWidth the narrowest
+// text column zooming produces.
+const (
+	maxZoom      = 5
+	minTextWidth = 20
+)
+
+// textColumnWidth returns the width the text is wrapped to inside a
+// main area innerWidth cells wide, less the split view's TOC pane and
+// the margins: each zoom level takes away a tenth of the width, as a
+// larger font would, down to minTextWidth.
+func (m Model) textColumnWidth(innerWidth int) int {
+	innerWidth = max(0, innerWidth-m.gutterWidth()-m.tocPaneWidth(innerWidth))
+	left, right := m.margins(innerWidth)
+	innerWidth -= left + right
+	width := innerWidth * (10 - m.zoom) / 10
+	return min(innerWidth, max(width, minTextWidth))
+}
+
+// margins returns the configured blank columns left and right of the
+// text in width cells, or none when they would leave the text less than
+// minTextWidth.
+func (m Model) margins(width int) (left, right int) {
+	if width-m.leftMargin-m.rightMargin < minTextWidth {
+		return 0, 0
+	}
+	return m.leftMargin, m.rightMargin
+}
+
+// wrapText splits text into visual lines of at most width cells and
+// returns them with the rune offset at which each line starts. Explicit
+// newlines always end a line. With wordWrap, a line that would overflow
+// is broken at its last break opportunity: a space, which is dropped, or
+// a point canBreakBefore allows, such as between CJK ideographs or after
+// a hyphen, so words stay whole; words longer than a line, and all text
+// when wordWrap is off, are cut at the width. Combining marks take no
+// cells. Tabs advance to the next multiple of tabWidth but stay in the
+// returned lines; see expandTabs.
+func wrapText(text []rune, width int, wordWrap bool, tabWidth int) ([]string, []int) {
+	lines := make([]string, 0, len(text)/width+1)
+	offsets := make([]int, 0, cap(lines))
 
 	var (
-		lineRunes       []rune
-		col             int // display width in cells
-		lineStartOffset int
+		lineRunes []rune
+		col       int // display width of lineRunes in cells
+		lineStart int // rune offset of lineRunes[0]
+		// The last break opportunity in lineRunes: the line would end
+		// before breakAt and the next start at resumeAt, which skips a
+		// space.
+		breakAt, resumeAt = -1, -1
 	)
-
-	flushLine := func() {
-		lines = append(lines, string(lineRunes))
-		offsets = append(offsets, lineStartOffset)
-		lineRunes = lineRunes[:0]
-		col = 0
-		lineStartOffset = 0
+	// runeWidth returns the cells r occupies when it starts at col.
+	runeWidth := func(r rune, col int) int {
+		switch {
+		case r == '\t' && tabWidth > 0:
+			return min(tabAdvance(col, tabWidth), width)
+		case isCombining(r):
+			// E.g. Thai vowel and tone marks, drawn over the letter.
+			return 0
+		}
+		return max(1, runewidth.RuneWidth(r))
+	}
+	emit := func(n int) {
+		lines = append(lines, string(lineRunes[:n]))
+		offsets = append(offsets, lineStart)
 	}
 
-	currentOffset := 0
-	for _, r := range m.textRunes {
+	for offset, r := range text {
 		if r == '\n' {
 			// End current visual line on explicit newline.
-			flushLine()
-			currentOffset++
-			lineStartOffset = currentOffset
+			emit(len(lineRunes))
+			lineRunes = lineRunes[:0]
+			col = 0
+			lineStart = offset + 1
+			breakAt = -1
 			continue
 		}
 
-		rw := runewidth.RuneWidth(r)
-		if rw <= 0 {
-			rw = 1
+		canBreak := wordWrap && len(lineRunes) > 0 && offset > 0 &&
+			canBreakBefore(runeBefore(text, offset-1), text[offset-1], r)
+		rw := runeWidth(r, col)
+		if col > 0 && col+rw > width {
+			switch {
+			case wordWrap && r == ' ':
+				// Break at this space and drop it.
+				emit(len(lineRunes))
+				lineRunes = lineRunes[:0]
+				col = 0
+				lineStart = offset + 1
+				breakAt = -1
+				continue
+			case canBreak:
+				// Break right before this rune.
+				emit(len(lineRunes))
+				lineRunes = lineRunes[:0]
+				col = 0
+				lineStart = offset
+				breakAt = -1
+			case wordWrap && breakAt > 0:
+				// Move the partial word after the last break
+				// opportunity to the next line.
+				emit(breakAt)
+				rest := append([]rune(nil), lineRunes[resumeAt:]...)
+				lineStart += resumeAt
+				lineRunes = append(lineRunes[:0], rest...)
+				col = 0
+				for _, rr := range lineRunes {
+					col += runeWidth(rr, col)
+				}
+				breakAt = -1
+			}
+			if col > 0 && col+rw > width {
+				// No usable space: cut the word at the width.
+				emit(len(lineRunes))
+				lineRunes = lineRunes[:0]
+				col = 0
+				lineStart = offset
+				breakAt = -1
+			}
+			rw = runeWidth(r, col)
 		}
 
-		// If adding this rune would exceed the inner width, flush the
-		// current line and start a new one at this rune offset.
-		if col > 0 && col+rw > innerWidth {
-			flushLine()
-			lineStartOffset = currentOffset
+		switch {
+		case r == ' ':
+			breakAt, resumeAt = len(lineRunes), len(lineRunes)+1
+		case canBreak && len(lineRunes) > 0:
+			breakAt, resumeAt = len(lineRunes), len(lineRunes)
 		}
-
 		lineRunes = append(lineRunes, r)
 		col += rw
-		currentOffset++
 	}
 
 	// Flush any remaining runes as the last line.
 	if len(lineRunes) > 0 {
-		flushLine()
+		emit(len(lineRunes))
 	}
+	return lines, offsets
+}
 
-	m.lines = lines
-	m.lineOffsets = offsets
-	if m.topLine >= len(m.lines) {
-		m.topLine = max(0, len(m.lines)-1)
+// runeBefore returns the rune before text[i], or 0 at the start.
+func runeBefore(text []rune, i int) rune {
+	if i <= 0 {
+		return 0
+	}
+	return text[i-1]
+}
+
+// handleMouse scrolls with the mouse wheel. In the reading view a step
+// scrolls scrollLines lines; while a menu or dialog is open it acts like
+// the Up and Down keys so the wheel moves the selection. Prompts ignore
+// the wheel.
+func (m *Model) handleMouse(msg tea.MouseMsg) {
+	if msg.Action != tea.MouseActionPress {
+		return
+	}
+	var key tea.KeyType
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		key = tea.KeyUp
+	case tea.MouseButtonWheelDown:
+		key = tea.KeyDown
+	default:
+		return
+	}
+	if m.inputMode || m.confirmOpen {
+		return
+	}
+	if m.menuOpen || m.helpOpen || m.statsOpen || m.metadataOpen || m.configOpen || m.browserOpen || m.tocOpen || m.bookmarksOpen || m.notesOpen || m.recentOpen || m.currentBook == nil {
+		m.handleKey(tea.KeyMsg{Type: key})
+		return
+	}
+	delta := m.scrollLines
+	if key == tea.KeyUp {
+		delta = -delta
+	}
+	m.scrollBy(delta)
+}
+
+// scrollBy moves the viewport by delta lines, clamped to the text.
+func (m *Model) scrollBy(delta int) {
+	top := min(max(m.topLine+delta, 0), max(0, len(m.lines)-1))
+	if top != m.topLine {
+		fromLine := m.topLine
+		m.topLine = top
+		m.updateCurrentPositionFromTopLine()
+		m.countScrolledText(fromLine)
+	}
+}
+
+// tabAdvance returns how many cells a tab at column col occupies, i.e.
+// the distance to the next tab stop.
+func tabAdvance(col, tabWidth int) int {
+	return tabWidth - col%tabWidth
+}
+
+// expandTabs replaces the tabs in a wrapped line with spaces up to the
+// next tab stop. It also returns, for each rune index of line (and for
+// its end), the corresponding rune index in the result; the map is nil
+// when line has no tabs.
+func expandTabs(line string, tabWidth int) (string, []int) {
+	if !strings.ContainsRune(line, '\t') || tabWidth <= 0 {
+		return line, nil
+	}
+	var b strings.Builder
+	indexMap := make([]int, 0, len(line)+1)
+	col, out := 0, 0
+	for _, r := range line {
+		indexMap = append(indexMap, out)
+		if r == '\t' {
+			n := tabAdvance(col, tabWidth)
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+			out += n
+			continue
+		}
+		b.WriteRune(r)
+		col += max(1, runewidth.RuneWidth(r))
+		out++
+	}
+	indexMap = append(indexMap, out)
+	return b.String(), indexMap
+}
+
+// scrollToEnd moves the viewport to the last wrapped line.
+func (m *Model) scrollToEnd() {
+	maxTop := max(0, len(m.lines)-1)
+	if m.topLine != maxTop {
+		m.topLine = maxTop
+		m.updateCurrentPositionFromTopLine()
+		m.startReadingSession()
+	}
+}
+
+// gotoPercent jumps to the given percentage (0–100) of the book text.
+func (m *Model) gotoPercent(input string) {
+	if m.currentBook == nil {
+		m.setStatus(m.tr(i18n.MsgGotoNoBook))
+		return
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(input), "%"))
+	if err != nil || percent < 0 || percent > 100 {
+		m.setStatus(m.tr(i18n.MsgGotoInvalid))
+		return
+	}
+	total := m.currentBook.Book.TotalCharacters
+	if total <= 0 {
+		total = len(m.textRunes)
+	}
+	offset := percent * total / 100
+	m.jumpToPosition(m.absoluteOffsetToPosition(offset))
+	m.setStatus(m.tr(i18n.MsgJumpedToPercent, percent))
+}
+
+// gotoChapter jumps to the start of the chapter whose 1-based number
+// is given in input.
+func (m *Model) gotoChapter(input string) {
+	if m.currentBook == nil {
+		m.setStatus(m.tr(i18n.MsgGotoNoBook))
+		return
+	}
+	chapters := m.currentBook.Book.Chapters
+	n, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || n < 1 || n > len(chapters) {
+		m.setStatus(m.tr(i18n.MsgGotoChapterInvalid, len(chapters)))
+		return
+	}
+	m.jumpToPosition(reader.Position{ChapterIndex: n - 1, OffsetInChapter: 0})
+	if title := chapters[n-1].Title; title != "" {
+		m.setStatus(m.tr(i18n.MsgJumpedToChapter, n, title))
+	} else {
+		m.setStatus(m.tr(i18n.MsgJumpedToChapterN, n))
+	}
+}
+
+// gotoLine jumps to the visual line whose 0-based index is given in
+// input, as Vim's ":" does.
+func (m *Model) gotoLine(input string) {
+	if m.currentBook == nil || len(m.lines) == 0 {
+		m.setStatus(m.tr(i18n.MsgGotoNoBook))
+		return
+	}
+	last := len(m.lines) - 1
+	n, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil {
+		m.setStatus(m.tr(i18n.MsgGotoLineInvalid, last))
+		return
+	}
+	if n < 0 || n > last {
+		m.setStatus(m.tr(i18n.MsgGotoLineOutOfRange, n))
+		return
+	}
+	m.topLine = n
+	m.updateCurrentPositionFromTopLine()
+	m.startReadingSession()
+	m.setStatus(m.tr(i18n.MsgJumpedToLine, n, last))
+}
+
+// dialogListRows returns how many list entries a dialog in the main
+// area can show; the last row is reserved for the dialog's status line.
+func (m Model) dialogListRows() int {
+	return max(1, m.visibleLineCount()-1)
+}
+
+// scrollTopFor returns the first visible entry of a list viewport with
+// the given number of rows, scrolling as little as possible from top so
+// that the selected entry is visible.
+func scrollTopFor(top, selected, rows int) int {
+	if rows <= 0 {
+		return selected
+	}
+	if selected < top {
+		return selected
+	}
+	if selected >= top+rows {
+		return selected - rows + 1
 	}
+	return max(0, top)
 }
 
 // visibleLineCount returns how many text lines fit inside the bordered
 // main area.
 func (m Model) visibleLineCount() int {
-	innerHeight := m.height - 3
-	if innerHeight < 1 {
-		innerHeight = 1
+	// The menu bar, title bar, top and bottom borders, and status bar
+	// take one line each, as does the tab bar when shown; the remaining
+	// lines are available for content.
+	if m.showTabBar() {
+		return max(0, m.height-6)
 	}
-	// One line is used by the bottom border; the remaining lines are
-	// available for content.
-	return max(0, innerHeight-1)
+	return max(0, m.height-5)
 }
 
 // updateCurrentPositionFromTopLine updates the logical Position based
@@ -908,7 +2960,43 @@ func (m *Model) updateCurrentPositionFromTopLine() {
 		idx = len(m.lineOffsets) - 1
 	}
 	abs := m.lineOffsets[idx]
+	if time.Since(m.lastReadTime) > maxReadingPause {
+		m.startReadingSession()
+	}
 	m.currentPos = m.absoluteOffsetToPosition(abs)
+	m.lastReadTime = time.Now()
+
+	elapsed := time.Since(m.sessionStartTime)
+	if read := abs - m.sessionStartOffset; read > 0 && elapsed >= minReadingSession {
+		m.readingCPM = float64(read) / elapsed.Minutes()
+	}
+}
+
+// minReadingSession is how long a reading session must last before its
+// speed is used for the time-left estimate.
+const minReadingSession = 30 * time.Second
+
+// maxReadingPause is the longest time without moving on that still
+// counts as reading; a longer pause starts a new reading session.
+const maxReadingPause = 5 * time.Minute
+
+// startReadingSession starts measuring reading speed from the current
+// position.
+func (m *Model) startReadingSession() {
+	m.sessionStartTime = time.Now()
+	m.lastReadTime = m.sessionStartTime
+	m.sessionStartOffset = m.positionToAbsoluteOffset(m.currentPos)
+}
+
+// minutesLeft estimates the minutes needed to read from the current
+// position to the end of the book at the measured reading speed. ok is
+// false while the speed is unknown.
+func (m Model) minutesLeft() (minutes int, ok bool) {
+	if m.currentBook == nil || m.readingCPM <= 0 {
+		return 0, false
+	}
+	left := m.currentBook.Book.TotalCharacters - m.positionToAbsoluteOffset(m.currentPos)
+	return int(math.Ceil(float64(max(0, left)) / m.readingCPM)), true
 }
 
 // jumpToPosition moves the viewport so that the given logical
@@ -918,17 +3006,14 @@ func (m *Model) jumpToPosition(pos reader.Position) {
 		return
 	}
 	abs := m.positionToAbsoluteOffset(pos)
-	// Find the first visual line whose starting offset is at or after
-	// the target offset.
-	line := 0
-	for i, off := range m.lineOffsets {
-		if off >= abs {
-			line = i
-			break
-		}
-	}
-	m.topLine = line
+	// Find the visual line containing the target offset: the last line
+	// whose starting offset is at or before it.
+	line := sort.Search(len(m.lineOffsets), func(i int) bool {
+		return m.lineOffsets[i] > abs
+	}) - 1
+	m.topLine = max(0, line)
 	m.updateCurrentPositionFromTopLine()
+	m.startReadingSession()
 }
 
 // positionToAbsoluteOffset converts a logical Position into a rune
@@ -944,6 +3029,26 @@ func (m Model) positionToAbsoluteOffset(pos reader.Position) int {
 	return ch.Offset + pos.OffsetInChapter
 }
 
+// clampPosition fits pos into the current book, e.g. after a reload
+// changed the chapter layout: a chapter index past the end moves to the
+// end of the last chapter, and an offset past the chapter's end moves
+// to its last rune.
+func (m Model) clampPosition(pos reader.Position) reader.Position {
+	if m.currentBook == nil || len(m.currentBook.Book.Chapters) == 0 {
+		return reader.Position{}
+	}
+	chapters := m.currentBook.Book.Chapters
+	if pos.ChapterIndex < 0 {
+		return reader.Position{}
+	}
+	if pos.ChapterIndex >= len(chapters) {
+		pos.ChapterIndex = len(chapters) - 1
+		pos.OffsetInChapter = chapters[pos.ChapterIndex].Length
+	}
+	pos.OffsetInChapter = min(max(pos.OffsetInChapter, 0), max(0, chapters[pos.ChapterIndex].Length-1))
+	return pos
+}
+
 // absoluteOffsetToPosition converts a rune offset into a logical
 // Position by finding the containing chapter and offset within it.
 func (m Model) absoluteOffsetToPosition(offset int) reader.Position {
@@ -954,7 +3059,9 @@ func (m Model) absoluteOffsetToPosition(offset int) reader.Position {
 	if len(chapters) == 0 {
 		return reader.Position{}
 	}
-	chapterIndex := 0
+	// Offsets past the last chapter (e.g. the very end of the book)
+	// belong to the last chapter.
+	chapterIndex := len(chapters) - 1
 	for i, ch := range chapters {
 		if offset < ch.Offset+ch.Length {
 			chapterIndex = i
@@ -976,12 +3083,12 @@ func (m Model) absoluteOffsetToPosition(offset int) reader.Position {
 // pseudo-graphics borders, and a status bar at the bottom.
 func (m Model) View() string {
 	if m.width <= 0 || m.height <= 0 {
-		return "thujareader – initializing..."
+		return m.tr(i18n.LabelInitializing)
 	}
 
-	if m.height < 3 {
+	if m.height < 5 {
 		// Not enough space to render full layout; show a compact message.
-		return "Terminal too small for thujareader UI. Resize the window."
+		return m.tr(i18n.LabelTerminalTooSmall)
 	}
 
 	var b strings.Builder
@@ -990,103 +3097,228 @@ func (m Model) View() string {
 	b.WriteString(m.theme.applyMenuBar(m.renderMenuBar()))
 	b.WriteRune('\n')
 
+	// Title bar naming the open book.
+	b.WriteString(m.theme.applyTitleBar(m.renderTitleBar()))
+	b.WriteRune('\n')
+
+	// Tab bar, when several books are open.
+	if m.showTabBar() {
+		b.WriteString(m.renderTabBar())
+		b.WriteRune('\n')
+	}
+
 	// Main area bordered with pseudo-graphics.
-	top := string(m.theme.borderTopLeft) + strings.Repeat(string(m.theme.borderHorizontal), max(0, m.width-2)) + string(m.theme.borderTopRight)
-	bottom := string(m.theme.borderBottomLeft) + strings.Repeat(string(m.theme.borderHorizontal), max(0, m.width-2)) + string(m.theme.borderBottomRight)
-	b.WriteString(top)
+	top := m.renderTopBorder()
+	bottom := m.renderBottomBorder()
+	b.WriteString(m.theme.applyMainArea(top))
 	b.WriteRune('\n')
 
-	innerHeight := m.height - 3 // minus top menu and bottom border + status bar
-	if innerHeight < 1 {
-		innerHeight = 1
+	vertical := string(m.theme.borderVertical)
+	for _, row := range m.renderMainRows(max(0, m.width-2)) {
+		b.WriteString(m.theme.applyMainArea(vertical + row + vertical))
+		b.WriteRune('\n')
 	}
 
-	for i := 0; i < innerHeight-1; i++ {
-		b.WriteRune(m.theme.borderVertical)
+	b.WriteString(m.theme.applyMainArea(bottom))
 
-		innerWidth := max(0, m.width-2)
-		// When a menu is open, render its items in the top lines of the
-		// main area so that selecting a menu visibly opens a dropdown.
-		if m.menuOpen && m.activeMenu >= 0 && m.activeMenu < len(m.menus) {
-			items := m.menus[m.activeMenu].items
-			if i < len(items) {
-				label := items[i].label
-
-				// Indicate the active item with a leading marker; other
-				// items are padded with a space for alignment.
-				var line string
-				if i == m.activeItem {
-					line = ">" + label
-				} else {
-					line = " " + label
-				}
+	// Status bar on the last line.
+	b.WriteRune('\n')
+	b.WriteString(m.theme.applyStatusBar(m.renderStatusBar()))
+
+	return b.String()
+}
 
-				b.WriteString(padOrTrim(line, innerWidth))
-			} else {
-				b.WriteString(strings.Repeat(" ", innerWidth))
+// renderMainRows renders the rows inside the bordered main area: an
+// open menu, the active dialog, or the book text, with single-line
+// prompts (loading, confirmation, input) overlaid on the first row.
+func (m Model) renderMainRows(innerWidth int) []string {
+	height := m.visibleLineCount()
+
+	// When a menu is open, render its items in the top lines of the
+	// main area so that selecting a menu visibly opens a dropdown.
+	if m.menuOpen && m.activeMenu >= 0 && m.activeMenu < len(m.menus) {
+		items := m.menus[m.activeMenu].items
+		entries := make([]render.MenuEntry, len(items))
+		for i, item := range items {
+			entries[i] = render.MenuEntry{Label: item.label, Hint: keyHint(m.keyBindings[item.command])}
+		}
+		return render.RenderMenuDropdown(entries, m.activeItem, height, innerWidth)
+	}
+
+	var rows []string
+	switch {
+	case m.noteEditing:
+		rows = m.renderNoteEditor(height, innerWidth)
+	case m.helpOpen:
+		rows = render.RenderContent(m.helpLines(), m.helpTopLine, height, innerWidth)
+	case m.statsOpen && m.currentBook != nil:
+		rows = render.RenderContent(m.statsLines(), 0, height, innerWidth)
+	case m.metadataOpen && m.currentBook != nil:
+		rows = render.RenderContent(m.metadataLines(innerWidth), 0, height, innerWidth)
+	case m.configOpen:
+		top := scrollTopFor(m.configTop, m.configIndex, m.dialogListRows())
+		rows = render.RenderTOCDialog(m.configLabels(), m.configIndex, top, height, innerWidth, m.theme.decor())
+	case m.browserOpen:
+		rows = render.RenderFileBrowserDialog(m.browser.dir, m.browser.labels(), m.browser.selected, m.browser.top, height, innerWidth, m.tr(i18n.LabelBrowserEmpty), m.theme.decor())
+	case m.tocOpen && m.currentBook != nil:
+		toc := m.currentBook.TOC
+		labels := make([]string, len(toc))
+		for i, entry := range toc {
+			labels[i] = entry.Label
+		}
+		top := scrollTopFor(m.tocTop, m.tocIndex, m.dialogListRows())
+		rows = render.RenderTOCDialog(labels, m.tocIndex, top, height, innerWidth, m.theme.decor())
+	case m.bookmarksOpen && m.currentBook != nil:
+		list := m.currentBookmarks()
+		names := make([]string, len(list))
+		for i, bm := range list {
+			names[i] = bm.Name
+			if bm.FromTOC {
+				names[i] = m.tr(i18n.LabelTOCBookmark, bm.Name)
+			}
+		}
+		top := scrollTopFor(m.bookmarkTop, m.bookmarkIndex, height)
+		rows = render.RenderBookmarksDialog(names, m.bookmarkIndex, top, height, innerWidth)
+	case m.notesOpen && m.currentBook != nil:
+		top := scrollTopFor(m.noteTop, m.noteIndex, m.dialogListRows())
+		rows = render.RenderTOCDialog(m.noteLabels(), m.noteIndex, top, height, innerWidth, m.theme.decor())
+	case m.recentOpen:
+		files := make([]render.RecentFile, len(m.recentFiles))
+		for i, path := range m.recentFiles {
+			files[i].Path = path
+			if i < len(m.recentFileMtimes) {
+				files[i].ModTime = m.recentFileMtimes[i]
+			}
+		}
+		top := scrollTopFor(m.recentTop, m.recentIndex, height)
+		rows = render.RenderRecentFilesDialog(files, m.recentIndex, top, height, innerWidth, m.theme.decor())
+	case m.currentBook != nil:
+		// Expand tabs before padding so widths are right, and keep each
+		// line's index map so highlights still land on the right runes.
+		var display []string
+		var indexMaps [][]int
+		for i := m.topLine; i >= 0 && i < len(m.lines) && i < m.topLine+height; i++ {
+			line, indexMap := expandTabs(m.lines[i], m.tabWidth)
+			display = append(display, line)
+			indexMaps = append(indexMaps, indexMap)
+		}
+		textWidth := m.textColumnWidth(innerWidth)
+		rows = render.RenderContent(display, 0, height, textWidth)
+		for i := range display {
+			if m.isRTL {
+				// Right-align, and move the highlights along with the text.
+				rows[i] = render.PadOrTrimRTL(display[i], textWidth)
+				shift := textWidth - runewidth.StringWidth(display[i])
+				indexMaps[i] = shiftIndexMap(indexMaps[i], utf8.RuneCountInString(display[i]), shift)
 			}
-		} else if m.inputMode && i == 0 {
-			// Show a simple one-line input prompt at the top of the main
-			// area when collecting a file path.
-			line := m.inputPrompt + string(m.inputBuffer)
-			b.WriteString(padOrTrim(line, innerWidth))
-		} else if m.tocOpen && m.currentBook != nil {
-			// Render a simple TOC dialog: list of entries with the
-			// currently selected one highlighted.
-			idx := i
-			if idx >= 0 && idx < len(m.currentBook.TOC) {
-				entry := m.currentBook.TOC[idx]
-				label := entry.Label
-				if idx == m.tocIndex {
-					label = "> " + label
+			switch {
+			case m.selecting:
+				rows[i] = m.highlightSelection(rows[i], m.topLine+i, indexMaps[i])
+			case m.cursorMode:
+				rows[i] = m.highlightCursor(rows[i], m.topLine+i, indexMaps[i])
+			default:
+				// Search matches take precedence over code colors.
+				if row := m.highlightSearchMatches(rows[i], m.topLine+i, indexMaps[i]); row != rows[i] {
+					rows[i] = row
 				} else {
-					label = "  " + label
+					rows[i] = m.highlightCode(rows[i], m.topLine+i, indexMaps[i])
 				}
-				b.WriteString(padOrTrim(label, innerWidth))
-			} else {
-				b.WriteString(strings.Repeat(" ", innerWidth))
 			}
-		} else if m.bookmarksOpen && m.currentBook != nil {
-			// Render a simple bookmarks dialog: list of bookmark names with
-			// the currently selected one highlighted.
-			list := m.currentBookmarks()
-			idx := i
-			if idx >= 0 && idx < len(list) {
-				entry := list[idx]
-				label := entry.Name
-				if idx == m.bookmarkIndex {
-					label = "> " + label
+		}
+		// In a very narrow main area the gutter gets what is left.
+		if gutter := min(m.gutterWidth(), max(0, innerWidth-textWidth)); gutter > 0 {
+			noted := m.notedLines()
+			for i := range display {
+				mark := strings.Repeat(" ", gutter)
+				if noted[m.topLine+i] {
+					mark = render.PadOrTrim(" "+noteMarker, gutter)
+				}
+				if m.isRTL {
+					rows[i] = reverseGutter(mark) + rows[i]
 				} else {
-					label = "  " + label
+					rows[i] += mark
 				}
-				b.WriteString(padOrTrim(label, innerWidth))
-			} else {
-				b.WriteString(strings.Repeat(" ", innerWidth))
 			}
-		} else if m.currentBook != nil {
-			// Render wrapped book text starting from topLine.
-			idx := m.topLine + i
-			if idx >= 0 && idx < len(m.lines) {
-				line := m.lines[idx]
-				b.WriteString(padOrTrim(line, innerWidth))
-			} else {
-				b.WriteString(strings.Repeat(" ", innerWidth))
+			for i := len(display); i < len(rows); i++ {
+				rows[i] += strings.Repeat(" ", gutter)
 			}
-		} else {
-			b.WriteString(strings.Repeat(" ", innerWidth))
 		}
-
-		b.WriteRune(m.theme.borderVertical)
-		b.WriteRune('\n')
+		// Add the margins, and center a column narrowed by zooming
+		// between them.
+		pane := m.tocPaneWidth(innerWidth)
+		left, right := m.margins(innerWidth - pane - m.gutterWidth())
+		if zoomed := innerWidth - pane - textWidth - m.gutterWidth() - left - right; zoomed > 0 {
+			left += zoomed / 2
+			right += zoomed - zoomed/2
+		}
+		if left > 0 || right > 0 {
+			for i := range rows {
+				rows[i] = strings.Repeat(" ", left) + rows[i] + strings.Repeat(" ", right)
+			}
+		}
+		if pane > 0 {
+			for i, paneRow := range m.renderTOCPane(len(rows)) {
+				rows[i] = paneRow + rows[i]
+			}
+		}
+	case len(m.recentFiles) > 0 && !m.loadingInProgress:
+		rows = m.renderBookshelf(height, innerWidth)
+	default:
+		rows = render.RenderContent(nil, 0, height, innerWidth)
+		if m.acceptDrops && len(rows) > 0 {
+			hint := m.tr(i18n.LabelDropHere)
+			left := max(0, innerWidth-runewidth.StringWidth(hint)) / 2
+			rows[len(rows)/2] = render.PadOrTrim(strings.Repeat(" ", left)+hint, innerWidth)
+		}
 	}
 
-	b.WriteString(bottom)
+	if len(rows) == 0 {
+		return rows
+	}
+	switch {
+	case m.loadingInProgress:
+		rows[0] = render.PadOrTrim(m.tr(i18n.LabelLoading, m.loadingPath), innerWidth)
+	case m.confirmOpen:
+		rows[0] = render.PadOrTrim(m.confirmPrompt, innerWidth)
+	case m.inputMode:
+		// Show a simple one-line input prompt at the top of the main
+		// area when collecting a file path.
+		rows[0] = m.renderInputLine(innerWidth)
+	}
+	if m.popupOpen {
+		m.renderPopup(rows, innerWidth)
+	}
+	if m.searchNoMatch {
+		rows[len(rows)/2] = m.renderNoMatchOverlay(innerWidth)
+	}
+	return rows
+}
 
-	// Status bar on the last line.
-	b.WriteRune('\n')
-	b.WriteString(m.theme.applyStatusBar(m.renderStatusBar()))
+// renderInputLine renders the input prompt and buffer with the theme's
+// cursor at the insertion point. Padding is computed on the plain text
+// so the cursor's escape sequences do not count toward the width.
+func (m Model) renderInputLine(width int) string {
+	cursor := min(max(m.inputCursor, 0), len(m.inputBuffer))
+	before := m.inputPrompt + string(m.inputBuffer[:cursor])
+	after := string(m.inputBuffer[cursor:])
+	plain := render.PadOrTrim(before+string(m.theme.cursorRune)+after, width)
+	beforeWidth := runewidth.StringWidth(before)
+	if beforeWidth+runewidth.RuneWidth(m.theme.cursorRune) > width {
+		// The cursor does not fit; show the line without it.
+		return plain
+	}
+	rest := runewidth.TruncateLeft(plain, beforeWidth+runewidth.RuneWidth(m.theme.cursorRune), "")
+	return before + m.theme.applyCursor(string(m.theme.cursorRune)) + rest
+}
 
-	return b.String()
+// renderNoMatchOverlay returns a row with the "no matches" notice
+// centered and highlighted.
+func (m Model) renderNoMatchOverlay(width int) string {
+	text := runewidth.Truncate(m.tr(i18n.LabelNoMatches, m.lastSearch), width, "…")
+	textWidth := runewidth.StringWidth(text)
+	left := (width - textWidth) / 2
+	right := width - textWidth - left
+	return strings.Repeat(" ", left) + m.theme.applyHighlight(text) + strings.Repeat(" ", right)
 }
 
 func (m Model) renderMenuBar() string {
@@ -1100,39 +3332,184 @@ func (m Model) renderMenuBar() string {
 		}
 	}
 	line := strings.Join(segments, "")
-	return padOrTrim(line, m.width)
+	return render.PadOrTrim(line, m.width)
+}
+
+// renderTopBorder returns the top border of the main area with the
+// current chapter's title centered in it, e.g. "┌── The Journey ──┐".
+// Untitled chapters show their number ("Chapter 3"), and books with a
+// single untitled chapter the book title; with no book, or no room, the
+// border is plain.
+func (m Model) renderTopBorder() string {
+	inner := max(0, m.width-2)
+	title := ""
+	if m.currentBook != nil {
+		chapters := m.currentBook.Book.Chapters
+		switch idx := m.currentPos.ChapterIndex; {
+		case idx >= 0 && idx < len(chapters) && chapters[idx].Title != "":
+			title = chapters[idx].Title
+		case idx < 0 || idx >= len(chapters) || len(chapters) == 1:
+			title = strings.TrimSpace(m.currentBook.Book.Title)
+		default:
+			title = m.tr(i18n.LabelChapter, idx+1)
+		}
+	}
+	return m.borderWithLabel(m.theme.borderTopLeft, m.theme.borderTopRight, title, inner)
+}
+
+// Widths of the progress bar in the bottom border: it takes up to
+// progressBarWidth cells and is left out when fewer than
+// minProgressBarWidth are free.
+const (
+	progressBarWidth    = 20
+	minProgressBarWidth = 5
+)
+
+// renderBottomBorder returns the bottom border of the main area with
+// the current page and a bar showing the progress through the book,
+// e.g. "└── Page 12/45 ████░░░░░░ ──┘", where a page is one screenful
+// of wrapped lines. The bar shrinks, and then disappears, as the window
+// narrows.
+func (m Model) renderBottomBorder() string {
+	inner := max(0, m.width-2)
+	label := ""
+	if page, total := m.pageNumbers(); total > 0 {
+		label = m.tr(i18n.LabelPage, page, total)
+		if width := m.progressBarCells(); width > 0 {
+			label += " " + m.progressBar(width)
+		}
+	}
+	return m.borderWithLabel(m.theme.borderBottomLeft, m.theme.borderBottomRight, label, inner)
+}
+
+// progressBarCells returns the width of the progress bar in the bottom
+// border, or 0 when it is left out, because the window is too narrow or
+// the book's size is unknown.
+func (m Model) progressBarCells() int {
+	page, total := m.pageNumbers()
+	if total == 0 || m.currentBook.Book.TotalCharacters <= 0 {
+		return 0
+	}
+	// borderWithLabel needs four cells besides the label, and the bar
+	// one more to separate it from the page.
+	label := m.tr(i18n.LabelPage, page, total)
+	width := min(progressBarWidth, max(0, m.width-2)-4-runewidth.StringWidth(label)-1)
+	if width < minProgressBarWidth {
+		return 0
+	}
+	return width
+}
+
+// progressBar returns a bar of width cells, filled in proportion to the
+// reading position, or "" when the book's size is unknown.
+func (m Model) progressBar(width int) string {
+	if m.currentBook == nil || m.currentBook.Book.TotalCharacters <= 0 || width <= 0 {
+		return ""
+	}
+	total := m.currentBook.Book.TotalCharacters
+	abs := min(max(m.positionToAbsoluteOffset(m.currentPos), 0), total)
+	filled := abs * width / total
+	return strings.Repeat(string(m.theme.scrollbarThumb), filled) +
+		strings.Repeat(string(m.theme.scrollbarTrack), width-filled)
+}
+
+// pageNumbers returns the 1-based page shown at topLine and the total
+// page count for the current wrapping, or zeros when no book is shown.
+func (m Model) pageNumbers() (int, int) {
+	perPage := m.visibleLineCount()
+	if m.currentBook == nil || perPage <= 0 || len(m.lines) == 0 {
+		return 0, 0
+	}
+	total := (len(m.lines) + perPage - 1) / perPage
+	page := min(m.topLine/perPage+1, total)
+	return page, total
+}
+
+// borderWithLabel builds a horizontal border of inner cells between the
+// given corners, with label centered in it surrounded by a space and at
+// least one border character on each side. Labels that do not fit are
+// truncated with "…"; an empty label yields a plain border.
+func (m Model) borderWithLabel(left, right rune, label string, inner int) string {
+	horizontal := string(m.theme.borderHorizontal)
+	available := inner - 4 // two padding dashes plus the spaces around the label
+	if label == "" || available <= 0 {
+		return string(left) + strings.Repeat(horizontal, inner) + string(right)
+	}
+	if runewidth.StringWidth(label) > available {
+		label = runewidth.Truncate(label, available, "…")
+	}
+	label = " " + label + " "
+	dashes := inner - runewidth.StringWidth(label)
+	leftDashes := dashes / 2
+	return string(left) +
+		strings.Repeat(horizontal, leftDashes) +
+		m.theme.applyBorderTitle(label) +
+		strings.Repeat(horizontal, dashes-leftDashes) +
+		string(right)
+}
+
+// renderTitleBar returns the centered " Title – Author " line shown
+// above the main area, or the program name when no book is open.
+func (m Model) renderTitleBar() string {
+	title := "thujareader"
+	if m.currentBook != nil {
+		book := m.currentBook.Book
+		title = strings.TrimSpace(book.Title)
+		if title == "" && m.currentBook.SourcePath != "" {
+			title = filepath.Base(m.currentBook.SourcePath)
+		}
+		if author := strings.TrimSpace(book.Author); author != "" {
+			title += " – " + author
+		}
+	}
+	title = " " + title + " "
+	if runewidth.StringWidth(title) > m.width {
+		title = runewidth.Truncate(title, m.width, "…")
+	}
+	left := (m.width - runewidth.StringWidth(title)) / 2
+	return render.PadOrTrim(strings.Repeat(" ", max(0, left))+title, m.width)
 }
 
 func (m Model) renderStatusBar() string {
 	text := m.statusLine
+	if m.loadingInProgress {
+		text = string(spinnerFrames[m.spinnerFrame]) + " " + text
+	}
 	location := ""
-	if m.currentBook != nil && len(m.currentBook.Book.Chapters) > 0 {
-		// Compute approximate progress percentage based on
-		// TotalCharacters and current position.
+	if m.currentBook != nil {
+		location = m.tr(i18n.LabelSearchModeTag, m.tr(m.searchMode.label())) + " "
+		if m.autoScroll {
+			location = m.tr(i18n.LabelAutoScrollOn) + " " + location
+		}
 		book := m.currentBook.Book
-		if book.TotalCharacters > 0 {
-			abs := m.positionToAbsoluteOffset(m.currentPos)
-			if abs < 0 {
-				abs = 0
-			}
-			if abs > book.TotalCharacters {
-				abs = book.TotalCharacters
-			}
-			percent := (abs * 100) / book.TotalCharacters
-			chapterIndex := m.currentPos.ChapterIndex
-			chapterLabel := ""
-			if chapterIndex >= 0 && chapterIndex < len(book.Chapters) {
-				ch := book.Chapters[chapterIndex]
-				if strings.TrimSpace(ch.Title) != "" {
-					chapterLabel = ch.Title
-				} else {
-					chapterLabel = "Chapter " + itoa(chapterIndex+1)
-				}
+		chapterIndex := m.currentPos.ChapterIndex
+		if chapterIndex >= 0 && chapterIndex < len(book.Chapters) {
+			location += m.chapterLabel(chapterIndex) + " "
+		}
+		// The progress bar in the bottom border shows how far into the
+		// book the position is; the percentage stands in for it when
+		// the window is too narrow for the bar.
+		if percent, ok := m.progressPercent(); ok {
+			if m.progressBarCells() == 0 {
+				location += m.tr(i18n.LabelPercent, percent) + " "
 			}
-			if chapterLabel != "" {
-				location = chapterLabel + " "
+			if minutes, ok := m.minutesLeft(); ok {
+				location += m.tr(i18n.LabelTimeLeft, minutes) + " "
 			}
-			location += itoa(percent) + "%"
+		} else {
+			location += m.tr(i18n.LabelUnknownPercent) + " "
+		}
+		location += m.dailyGoalLabel()
+		location = strings.TrimSpace(location)
+	}
+	if m.StatusProvider != nil {
+		var book *reader.Book
+		if m.currentBook != nil {
+			b := m.currentBook.Book
+			book = &b
+		}
+		if extra := strings.TrimSpace(m.StatusProvider(book, m.currentPos)); extra != "" {
+			location = strings.TrimSpace(extra + " " + location)
 		}
 	}
 
@@ -1140,23 +3517,38 @@ func (m Model) renderStatusBar() string {
 		// Place location info at the right edge, trimming or padding the
 		// main status text as needed. All widths are rune/column-aware.
 		locWidth := runewidth.StringWidth(location)
-		if locWidth > m.width {
-			location = runewidth.Truncate(location, m.width, "")
-			locWidth = runewidth.StringWidth(location)
-		}
-		available := m.width - locWidth - 1
-		if available < 0 {
-			available = 0
+		if locWidth >= m.width {
+			// No room for the status text and the space before location.
+			return render.PadOrTrim(location, m.width)
 		}
-		text = padOrTrim(text, available)
+		text = render.PadOrTrim(text, m.width-locWidth-1)
 		text += " " + location
 	} else {
-		text = padOrTrim(text, m.width)
+		text = render.PadOrTrim(text, m.width)
 	}
 
 	return text
 }
 
+// progressPercent returns how far into the book the current position
+// is, from 0 to 100. ok is false when no book is open or its size is
+// unknown (TotalCharacters == 0).
+func (m Model) progressPercent() (percent int, ok bool) {
+	return m.percentAt(m.currentPos)
+}
+
+// percentAt returns how far into the book pos is, from 0 to 100, with
+// the same conditions as progressPercent.
+func (m Model) percentAt(pos reader.Position) (percent int, ok bool) {
+	if m.currentBook == nil || m.currentBook.Book.TotalCharacters <= 0 {
+		return 0, false
+	}
+	total := m.currentBook.Book.TotalCharacters
+	abs := m.positionToAbsoluteOffset(pos)
+	abs = min(max(abs, 0), total)
+	return abs * 100 / total, true
+}
+
 // itoa is a small helper for integer-to-string conversion.
 func itoa(i int) string {
 	return strconv.Itoa(i)
@@ -1168,21 +3560,3 @@ func max(a, b int) int {
 	}
 	return b
 }
-
-// padOrTrim returns a version of s whose printable width (in terminal
-// cells) is exactly width, using runewidth to account for multi-byte
-// and wide runes. If s is wider, it is truncated; if it is narrower,
-// it is padded with spaces on the right.
-func padOrTrim(s string, width int) string {
-	if width <= 0 {
-		return ""
-	}
-	w := runewidth.StringWidth(s)
-	if w > width {
-		return runewidth.Truncate(s, width, "")
-	}
-	if w < width {
-		return s + strings.Repeat(" ", width-w)
-	}
-	return s
-}
@@ -0,0 +1,374 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+
+	"thujareader/internal/config"
+	"thujareader/internal/i18n"
+	"thujareader/internal/reader"
+)
+
+// testBook returns a one-chapter book with the given text.
+func testBook(text string) *reader.LoadedBook {
+	n := len([]rune(text))
+	return &reader.LoadedBook{
+		Book: reader.Book{
+			ID:              "test",
+			Title:           "Test",
+			Chapters:        []reader.Chapter{{Length: n}},
+			TotalCharacters: n,
+		},
+		Text: text,
+	}
+}
+
+// ansiEscape matches the color sequences themes add to rendered rows.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestNarrowWindowWithNotes(t *testing.T) {
+	m := NewModelWithInitialBook(testBook("Some text to wrap.\n"))
+	m.SetNotes(map[reader.BookID][]reader.Note{"test": {{BookID: "test", Text: "note"}}})
+	// Below two columns not even the side borders fit.
+	for width := 2; width <= 24; width++ {
+		next, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: 10})
+		nm := next.(Model)
+		for _, row := range strings.Split(nm.View(), "\n") {
+			if w := runewidth.StringWidth(ansiEscape.ReplaceAllString(row, "")); w > width {
+				t.Fatalf("width %d: row %q is %d cells wide", width, row, w)
+			}
+		}
+		textWidth := nm.textColumnWidth(width - 2)
+		if textWidth <= 0 {
+			continue
+		}
+		if textWidth < len("Some text to wrap.") && len(nm.lines) < 2 {
+			t.Errorf("width %d: text not wrapped beside the notes gutter: %q", width, nm.lines)
+		}
+		for _, line := range nm.lines {
+			if runewidth.StringWidth(line) > textWidth {
+				t.Errorf("width %d: line %q wider than the text column (%d)", width, line, textWidth)
+			}
+		}
+	}
+}
+
+func TestLooksLikeBook(t *testing.T) {
+	dir := t.TempDir()
+	mobi := make([]byte, 100)
+	copy(mobi[60:], "BOOKMOBI")
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"book.txt", []byte("Chapter 1\n\nIt was a dark night.\n"), true},
+		{"book.fb2", []byte(`<?xml version="1.0"?><FictionBook/>`), true},
+		{"book.epub", []byte("PK\x03\x04mimetypeapplication/epub+zip"), true},
+		{"book.mobi", mobi, true},
+		{"book.azw3", mobi, true},
+		{"cover.png", png, false},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, tt.data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := looksLikeBook(path); got != tt.want {
+			t.Errorf("looksLikeBook(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// binaryPlugin opens .bin files, which content sniffing would reject.
+type binaryPlugin struct{}
+
+func (binaryPlugin) CanOpen(path string) bool { return filepath.Ext(path) == ".bin" }
+
+func (binaryPlugin) Open(path string) (reader.LoadedBook, error) {
+	return *testBook("From a plugin.\n"), nil
+}
+
+func TestOpenPathUsesInjectedReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.bin")
+	if err := os.WriteFile(path, []byte{0, 1, 2, 3, 0xff}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	u := reader.NewDefaultUnifiedReader()
+	u.RegisterPlugin(binaryPlugin{})
+	m := NewModel()
+	m.SetUnifiedReader(u)
+	m.openPath(path)
+	if !m.loadingInProgress {
+		t.Fatalf("openPath(%s) did not start loading: %q", path, m.statusLine)
+	}
+}
+
+func TestGotoPercent(t *testing.T) {
+	var text strings.Builder
+	for i := range 200 {
+		fmt.Fprintf(&text, "Line %d of the book.\n", i+1)
+	}
+	m := NewModelWithInitialBook(testBook(text.String()))
+	lastLine := len(m.lines) - 1
+	currentLine := func() int {
+		return m.lineOfOffset(m.positionToAbsoluteOffset(m.currentPos))
+	}
+
+	m.gotoPercent("50")
+	m.gotoPercent("0")
+	if line := currentLine(); line != 0 || m.topLine != 0 {
+		t.Errorf("gotoPercent(0): line %d, top line %d; want 0, 0", line, m.topLine)
+	}
+	m.gotoPercent("100%")
+	if line := currentLine(); line != lastLine {
+		t.Errorf("gotoPercent(100): line %d, want %d", line, lastLine)
+	}
+
+	want := m.currentPos
+	for _, input := range []string{"-1", "101", "abc", "", "5O"} {
+		m.gotoPercent(input)
+		if m.currentPos != want {
+			t.Errorf("gotoPercent(%q) moved to %+v", input, m.currentPos)
+		}
+		if m.statusLine != m.tr(i18n.MsgGotoInvalid) {
+			t.Errorf("gotoPercent(%q): status %q, want the invalid input message", input, m.statusLine)
+		}
+	}
+}
+
+func TestOpenMenuByAltKeyNonASCII(t *testing.T) {
+	m := NewModel()
+	m.menus[1].label = "Édition"
+	m.menus[2].label = "Поиск"
+	m.openMenuByAltKey('п')
+	if !m.menuOpen || m.activeMenu != 2 {
+		t.Errorf("Alt+п: menu open %v, active %d; want menu 2", m.menuOpen, m.activeMenu)
+	}
+	m.openMenuByAltKey('é')
+	if m.activeMenu != 1 {
+		t.Errorf("Alt+é: active menu %d, want 1", m.activeMenu)
+	}
+}
+
+func TestSetBookReportsInvalidBookInStatus(t *testing.T) {
+	book := testBook("Some text.\n")
+	book.Book.Chapters[0].Length++
+	m := NewModelWithInitialBook(book)
+	if want := m.tr(i18n.MsgBookInvalid, "Test", reader.ValidateLoadedBook(*book)); m.statusLine != want {
+		t.Errorf("status %q, want %q", m.statusLine, want)
+	}
+}
+
+func TestProgressFallsBackToPercent(t *testing.T) {
+	m := NewModelWithInitialBook(testBook(strings.Repeat("Some words to read. ", 200)))
+	m.config.ReadingGoals.DailyCharsTarget = 1000
+	goal := m.dailyGoalLabel()
+	percent := m.tr(i18n.LabelPercent, 0)
+
+	m.width, m.height = 80, 25
+	m.reflowWrappedLines()
+	if m.progressBarCells() == 0 {
+		t.Fatal("no progress bar in an 80-column window")
+	}
+	if status := m.renderStatusBar(); strings.Contains(status, percent) || !strings.Contains(status, goal) {
+		t.Errorf("with the bar shown, status bar %q should have %q but no %q", status, goal, percent)
+	}
+
+	m.width = 20
+	m.reflowWrappedLines()
+	if cells := m.progressBarCells(); cells != 0 {
+		t.Fatalf("progress bar of %d cells in a 20-column window", cells)
+	}
+	if status := m.renderStatusBar(); !strings.Contains(status, percent) {
+		t.Errorf("without the bar, status bar %q should show %q", status, percent)
+	}
+}
+
+func TestTOCBookmarkOriginSurvivesRename(t *testing.T) {
+	book := testBook("Chapter one.\n")
+	book.TOC = []reader.TOCEntry{{Label: "One", BookID: "test"}}
+	m := NewModelWithInitialBook(book)
+	m.executeCommand(cmdAddBookmark)
+	m.executeCommand(cmdBookmarks)
+	if list := m.currentBookmarks(); len(list) != 2 || !list[0].FromTOC || list[1].FromTOC {
+		t.Fatalf("bookmarks %+v, want a TOC bookmark and a user one", list)
+	}
+
+	// A user bookmark named like a TOC entry is still the user's.
+	m.bookmarkIndex = 1
+	m.renameBookmark("toc:Mine")
+	m.executeCommand(cmdDeleteBookmark)
+	if list := m.currentBookmarks(); len(list) != 1 {
+		t.Fatalf("user bookmark renamed to toc:Mine was not deleted: %+v", list)
+	}
+
+	// A renamed TOC bookmark stays one, and stays undeletable.
+	m.bookmarkIndex = 0
+	m.renameBookmark("Renamed")
+	m.executeCommand(cmdDeleteBookmark)
+	if list := m.currentBookmarks(); len(list) != 1 || !list[0].FromTOC || list[0].Name != "Renamed" {
+		t.Errorf("bookmarks %+v, want the renamed TOC bookmark kept", list)
+	}
+}
+
+func TestBookLoadedReturnsQueuedCmds(t *testing.T) {
+	m := NewModel()
+	m.loadingInProgress = true
+	m.loadingPath = "book.txt"
+	m.queueCmd(func() tea.Msg { return nil })
+	_, cmd := m.Update(bookLoadedMsg{path: "book.txt", book: *testBook("Text.\n")})
+	if cmd == nil {
+		t.Fatal("Update(bookLoadedMsg) dropped the queued commands")
+	}
+}
+
+func TestConstructorLeavesColorSchemeUndetected(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AutoTheme = true
+	m := NewModelWithConfig(cfg, nil, nil, nil)
+	if m.colorScheme != "" {
+		t.Fatalf("NewModelWithConfig detected color scheme %q", m.colorScheme)
+	}
+	m.colorScheme = colorSchemeLight
+	m.applyTheme()
+	if want := themeByName("", colorSchemeLight); !reflect.DeepEqual(m.theme, want) {
+		t.Errorf("theme = %+v, want the light variant %+v", m.theme, want)
+	}
+}
+
+func TestBookmarksDialogScrollsToSelection(t *testing.T) {
+	book := testBook("Chapter text.\n")
+	for i := range 30 {
+		book.TOC = append(book.TOC, reader.TOCEntry{Label: fmt.Sprintf("Chapter %d", i+1), BookID: "test"})
+	}
+	m := NewModelWithInitialBook(book)
+	m.width, m.height = 40, 12
+	m.executeCommand(cmdBookmarks)
+	for range 25 {
+		m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	rows := m.renderMainRows(m.width - 2)
+	for _, row := range rows {
+		if strings.HasPrefix(row, "> ") {
+			return
+		}
+	}
+	t.Errorf("selected bookmark %d not shown in %q", m.bookmarkIndex, rows)
+}
+
+func TestBookmarkNameSkipsTOCBookmarks(t *testing.T) {
+	book := testBook("Chapter text.\n")
+	book.TOC = []reader.TOCEntry{{Label: "One", BookID: "test"}, {Label: "Two", BookID: "test"}}
+	m := NewModelWithInitialBook(book)
+	m.executeCommand(cmdAddBookmark)
+	list := m.currentBookmarks()
+	if got, want := list[len(list)-1].Name, m.tr(i18n.LabelBookmarkName, 1); got != want {
+		t.Errorf("first user bookmark named %q, want %q", got, want)
+	}
+}
+
+func TestOpenPathKeepsDollarInFileName(t *testing.T) {
+	t.Setenv("Roll", "")
+	path := filepath.Join(t.TempDir(), "Rock$Roll.txt")
+	if err := os.WriteFile(path, []byte("Some text.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel()
+	m.openPath(path)
+	if !m.loadingInProgress || m.loadingPath != path {
+		t.Errorf("openPath(%s) loading %q: %q", path, m.loadingPath, m.statusLine)
+	}
+}
+
+func TestExportTextRefusedWhileRunning(t *testing.T) {
+	dir := t.TempDir()
+	m := NewModelWithInitialBook(testBook("Some text.\n"))
+	m.exportText(filepath.Join(dir, "first.txt"))
+	running := m.textExport
+	m.exportText(filepath.Join(dir, "second.txt"))
+	defer running.file.Close()
+	if m.textExport != running {
+		t.Fatal("a second export replaced the running one")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "second.txt")); err == nil {
+		t.Error("the second export created its file")
+	}
+}
+
+func TestTopBorderShowsChapterTitleAsIs(t *testing.T) {
+	book := testBook("One.\nTwo.\n")
+	book.Book.Chapters = []reader.Chapter{
+		{Title: "Chapter One", Length: 5},
+		{Index: 1, Offset: 5, Length: 5},
+	}
+	m := NewModelWithInitialBook(book)
+	m.width = 60
+	if border := m.renderTopBorder(); !strings.Contains(border, " Chapter One ") || strings.Contains(border, "Chapter 1") {
+		t.Errorf("top border %q, want the title Chapter One as-is", border)
+	}
+	m.currentPos.ChapterIndex = 1
+	if border, want := m.renderTopBorder(), m.tr(i18n.LabelChapter, 2); !strings.Contains(border, want) {
+		t.Errorf("top border %q, want %q for an untitled chapter", border, want)
+	}
+}
+
+func TestReadingSpeedIgnoresIdleTime(t *testing.T) {
+	m := NewModelWithInitialBook(testBook(strings.Repeat("Some words to read. ", 400)))
+	m.width, m.height = 40, 12
+	m.reflowWrappedLines()
+	m.sessionStartTime = time.Now().Add(-20 * time.Minute)
+	m.lastReadTime = time.Now().Add(-15 * time.Minute)
+	m.scrollBy(3)
+	if m.readingCPM != 0 {
+		t.Errorf("reading speed %.1f cpm measured across a 15 minute pause", m.readingCPM)
+	}
+	if time.Since(m.sessionStartTime) > time.Minute {
+		t.Error("the pause did not start a new reading session")
+	}
+}
+
+func TestSearchExactByDefault(t *testing.T) {
+	m := NewModelWithInitialBook(testBook("one\ntwo, one two\n"))
+	m.performSearch("one two", true, false)
+	if want := len("one\ntwo, "); len(m.highlightedMatches) != 1 || m.highlightedMatches[0] != want {
+		t.Errorf("matches %v, want only the exact one at %d", m.highlightedMatches, want)
+	}
+	m.executeCommand(cmdToggleSearchWhitespace)
+	m.performSearch("one two", true, false)
+	if len(m.highlightedMatches) != 2 {
+		t.Errorf("matches %v, want both once Match Across Lines is on", m.highlightedMatches)
+	}
+}
+
+func TestRecentFilesDialogScrollsToSelection(t *testing.T) {
+	var files []string
+	for i := range 30 {
+		files = append(files, fmt.Sprintf("/books/book%d.txt", i+1))
+	}
+	m := NewModel()
+	m.recentLimit = len(files)
+	m.SetRecentFiles(files)
+	m.width, m.height = 40, 12
+	m.executeCommand(cmdRecentFiles)
+	for range 25 {
+		m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	rows := m.renderMainRows(m.width - 2)
+	for _, row := range rows {
+		if strings.HasPrefix(row, "> ") {
+			return
+		}
+	}
+	t.Errorf("selected recent file %d not shown in %q", m.recentIndex, rows)
+}
@@ -0,0 +1,321 @@
+package ui
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+
+	"thujareader/internal/i18n"
+	"thujareader/internal/reader"
+	"thujareader/internal/render"
+)
+
+// noteMarker marks lines with notes in the gutter, which takes
+// noteGutterWidth columns beside the text while the book has notes.
+const (
+	noteMarker      = "¶"
+	noteGutterWidth = 2
+)
+
+// SetNotes installs the persisted notes, keyed by book ID.
+func (m *Model) SetNotes(notes map[reader.BookID][]reader.Note) {
+	m.notes = maps.Clone(notes)
+	if m.notes == nil {
+		m.notes = make(map[reader.BookID][]reader.Note)
+	}
+	m.reflowKeepingPosition()
+}
+
+// ExportNotes returns a copy of the notes so that callers (e.g. main)
+// can persist them.
+func (m Model) ExportNotes() map[reader.BookID][]reader.Note {
+	out := make(map[reader.BookID][]reader.Note, len(m.notes))
+	for id, list := range m.notes {
+		if len(list) > 0 {
+			out[id] = slices.Clone(list)
+		}
+	}
+	return out
+}
+
+// currentNotes returns the notes of the open book, in reading order.
+func (m Model) currentNotes() []reader.Note {
+	if m.currentBook == nil {
+		return nil
+	}
+	return m.notes[m.currentBook.Book.ID]
+}
+
+// gutterWidth returns the width of the notes gutter, which is only
+// shown for books with notes.
+func (m Model) gutterWidth() int {
+	if len(m.currentNotes()) == 0 {
+		return 0
+	}
+	return noteGutterWidth
+}
+
+// reflowKeepingPosition rewraps the text, e.g. after the gutter
+// appeared or went away, and stays at the current position.
+func (m *Model) reflowKeepingPosition() {
+	if m.currentBook == nil {
+		return
+	}
+	pos := m.currentPos
+	m.reflowWrappedLines()
+	m.jumpToPosition(pos)
+}
+
+// notedLines returns the visual lines that contain a note.
+func (m Model) notedLines() map[int]bool {
+	out := make(map[int]bool)
+	for _, n := range m.currentNotes() {
+		out[m.lineOfOffset(m.positionToAbsoluteOffset(n.Pos))] = true
+	}
+	return out
+}
+
+// reverseGutter mirrors a gutter mark for right-to-left books, whose
+// gutter is on the left.
+func reverseGutter(mark string) string {
+	r := []rune(mark)
+	slices.Reverse(r)
+	return string(r)
+}
+
+// startNoteEditor opens the note editor on the note at index in the
+// open book's notes, or on a new note at the reading position when
+// index is -1.
+func (m *Model) startNoteEditor(index int) {
+	m.noteEditing = true
+	m.noteEditIndex = index
+	m.noteBuffer = nil
+	m.notePos = m.currentPos
+	if notes := m.currentNotes(); index >= 0 && index < len(notes) {
+		m.noteBuffer = []rune(notes[index].Text)
+		m.notePos = notes[index].Pos
+	}
+	m.noteCursor = len(m.noteBuffer)
+	m.setStatus(m.tr(i18n.MsgNoteHint))
+}
+
+// handleNoteEditKey edits the note text. Enter starts a new line,
+// Ctrl+S saves the note and Esc discards the changes.
+func (m *Model) handleNoteEditKey(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.noteEditing = false
+		m.setStatus(m.tr(i18n.MsgCancelled))
+	case tea.KeyCtrlS:
+		m.saveNote()
+	case tea.KeyEnter:
+		m.noteBuffer = slices.Insert(m.noteBuffer, m.noteCursor, '\n')
+		m.noteCursor++
+	case tea.KeyBackspace:
+		if m.noteCursor > 0 {
+			m.noteBuffer = slices.Delete(m.noteBuffer, m.noteCursor-1, m.noteCursor)
+			m.noteCursor--
+		}
+	case tea.KeyDelete:
+		if m.noteCursor < len(m.noteBuffer) {
+			m.noteBuffer = slices.Delete(m.noteBuffer, m.noteCursor, m.noteCursor+1)
+		}
+	case tea.KeyLeft:
+		m.noteCursor = max(m.noteCursor-1, 0)
+	case tea.KeyRight:
+		m.noteCursor = min(m.noteCursor+1, len(m.noteBuffer))
+	case tea.KeyHome:
+		m.noteCursor = m.noteLineStart(m.noteCursor)
+	case tea.KeyEnd:
+		m.noteCursor = m.noteLineEnd(m.noteCursor)
+	case tea.KeyUp:
+		if start := m.noteLineStart(m.noteCursor); start > 0 {
+			prev := m.noteLineStart(start - 1)
+			m.noteCursor = min(prev+m.noteCursor-start, start-1)
+		}
+	case tea.KeyDown:
+		if end := m.noteLineEnd(m.noteCursor); end < len(m.noteBuffer) {
+			col := m.noteCursor - m.noteLineStart(m.noteCursor)
+			m.noteCursor = min(end+1+col, m.noteLineEnd(end+1))
+		}
+	default:
+		if len(msg.Runes) > 0 {
+			m.noteBuffer = slices.Insert(m.noteBuffer, m.noteCursor, msg.Runes...)
+			m.noteCursor += len(msg.Runes)
+		}
+	}
+}
+
+// noteLineStart returns the offset of the start of the note line
+// containing offset.
+func (m Model) noteLineStart(offset int) int {
+	for offset > 0 && m.noteBuffer[offset-1] != '\n' {
+		offset--
+	}
+	return offset
+}
+
+// noteLineEnd returns the offset of the end of the note line containing
+// offset, i.e. of its newline or of the end of the text.
+func (m Model) noteLineEnd(offset int) int {
+	for offset < len(m.noteBuffer) && m.noteBuffer[offset] != '\n' {
+		offset++
+	}
+	return offset
+}
+
+// saveNote stores the edited note, keeping the notes in reading order,
+// and closes the editor. Saving an empty note deletes it.
+func (m *Model) saveNote() {
+	m.noteEditing = false
+	if m.currentBook == nil {
+		return
+	}
+	text := strings.TrimSpace(string(m.noteBuffer))
+	notes := slices.Clone(m.currentNotes())
+	if m.noteEditIndex >= 0 && m.noteEditIndex < len(notes) {
+		notes = slices.Delete(notes, m.noteEditIndex, m.noteEditIndex+1)
+	}
+	if text == "" {
+		m.setNotes(notes)
+		if m.noteEditIndex >= 0 {
+			m.setTransientStatus(m.tr(i18n.MsgNoteDeleted))
+		} else {
+			m.setStatus(m.tr(i18n.MsgNoteEmpty))
+		}
+		return
+	}
+	note := reader.Note{BookID: m.currentBook.Book.ID, Pos: m.notePos, Text: text}
+	offset := m.positionToAbsoluteOffset(note.Pos)
+	i, _ := slices.BinarySearchFunc(notes, offset, func(n reader.Note, off int) int {
+		return m.positionToAbsoluteOffset(n.Pos) - off
+	})
+	m.setNotes(slices.Insert(notes, i, note))
+	m.noteIndex = i
+	m.setTransientStatus(m.tr(i18n.MsgNoteSaved))
+}
+
+// setNotes replaces the open book's notes and rewraps the text when the
+// gutter appears or goes away.
+func (m *Model) setNotes(notes []reader.Note) {
+	gutter := m.gutterWidth()
+	if m.notes == nil {
+		m.notes = make(map[reader.BookID][]reader.Note)
+	}
+	m.notes[m.currentBook.Book.ID] = notes
+	if m.gutterWidth() != gutter {
+		m.reflowKeepingPosition()
+	}
+}
+
+// handleNotesKey handles keys in the notes dialog: Enter jumps to the
+// selected note, E edits and D deletes it.
+func (m *Model) handleNotesKey(msg tea.KeyMsg) bool {
+	notes := m.currentNotes()
+	if len(notes) == 0 {
+		m.notesOpen = false
+		return msg.Type == tea.KeyEsc
+	}
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.notesOpen = false
+	case tea.KeyUp:
+		m.noteIndex--
+	case tea.KeyDown:
+		m.noteIndex++
+	case tea.KeyEnter:
+		m.notesOpen = false
+		m.jumpToPosition(notes[min(max(m.noteIndex, 0), len(notes)-1)].Pos)
+		return true
+	case tea.KeyDelete:
+		m.deleteSelectedNote()
+	case tea.KeyRunes:
+		if len(msg.Runes) != 1 {
+			return false
+		}
+		switch msg.Runes[0] {
+		case 'e', 'E':
+			m.notesOpen = false
+			m.startNoteEditor(m.noteIndex)
+			return true
+		case 'd', 'D':
+			m.deleteSelectedNote()
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	m.noteIndex = min(max(m.noteIndex, 0), max(0, len(m.currentNotes())-1))
+	m.noteTop = scrollTopFor(m.noteTop, m.noteIndex, m.dialogListRows())
+	return true
+}
+
+// deleteSelectedNote deletes the note selected in the notes dialog and
+// closes the dialog when it was the last one.
+func (m *Model) deleteSelectedNote() {
+	notes := m.currentNotes()
+	if m.noteIndex < 0 || m.noteIndex >= len(notes) {
+		return
+	}
+	m.setNotes(slices.Delete(slices.Clone(notes), m.noteIndex, m.noteIndex+1))
+	if len(m.currentNotes()) == 0 {
+		m.notesOpen = false
+	}
+	m.setTransientStatus(m.tr(i18n.MsgNoteDeleted))
+}
+
+// noteLabels returns one line per note of the open book for the notes
+// dialog: the chapter and the start of the note text.
+func (m Model) noteLabels() []string {
+	notes := m.currentNotes()
+	labels := make([]string, len(notes))
+	for i, n := range notes {
+		first, _, _ := strings.Cut(n.Text, "\n")
+		labels[i] = m.chapterLabel(n.Pos.ChapterIndex) + ": " + first
+	}
+	return labels
+}
+
+// renderNoteEditor renders the note editor: a title row, then the note
+// text wrapped to the width with the cursor at the insertion point,
+// scrolled so that the cursor stays visible.
+func (m Model) renderNoteEditor(height, innerWidth int) []string {
+	if height <= 0 {
+		return nil
+	}
+	rows := []string{render.PadOrTrim(m.tr(i18n.LabelNoteEditor), innerWidth)}
+	// Leave a column for the cursor at the end of a full line.
+	lines, offsets := wrapText(m.noteBuffer, max(1, innerWidth-1), true, m.tabWidth)
+	if len(lines) == 0 || (m.noteBuffer[len(m.noteBuffer)-1] == '\n') {
+		lines = append(lines, "")
+		offsets = append(offsets, len(m.noteBuffer))
+	}
+	cursorLine := 0
+	for i, off := range offsets {
+		if off <= m.noteCursor {
+			cursorLine = i
+		}
+	}
+	textRows := max(0, height-1)
+	top := max(0, cursorLine-textRows+1)
+	for i := top; i < len(lines) && len(rows) < height; i++ {
+		if i != cursorLine {
+			rows = append(rows, render.PadOrTrim(lines[i], innerWidth))
+			continue
+		}
+		line := []rune(lines[i])
+		col := min(m.noteCursor-offsets[i], len(line))
+		before, after := string(line[:col]), string(line[col:])
+		cursor := string(m.theme.cursorRune)
+		pad := max(0, innerWidth-runewidth.StringWidth(before+cursor+after))
+		rows = append(rows, before+m.theme.applyCursor(cursor)+after+strings.Repeat(" ", pad))
+	}
+	for len(rows) < height {
+		rows = append(rows, strings.Repeat(" ", max(0, innerWidth)))
+	}
+	return rows
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"strings"
+
+	"thujareader/internal/i18n"
+	"thujareader/internal/render"
+)
+
+// popupWidth is the widest a popup gets, in cells.
+const popupWidth = 60
+
+// showPopup opens a popup over the main area with text under title,
+// e.g. a dictionary definition or a footnote. Any key closes it.
+func (m *Model) showPopup(title, text string) {
+	m.popupOpen = true
+	m.popupTitle = title
+	m.popupText = text
+	m.setStatus(m.tr(i18n.MsgPopupHint))
+}
+
+// closePopup closes the popup.
+func (m *Model) closePopup() {
+	m.popupOpen = false
+	if m.cursorMode {
+		m.setStatus(m.tr(i18n.MsgCursorHint))
+	} else {
+		m.setStatus(m.tr(i18n.MsgWelcome))
+	}
+}
+
+// renderPopup draws the popup in a bordered box centered over rows,
+// which are innerWidth cells wide, with its title in the top border.
+// Text too long for the main area is cut off.
+func (m Model) renderPopup(rows []string, innerWidth int) {
+	boxWidth := min(innerWidth, popupWidth)
+	textWidth := boxWidth - 4 // borders and a space on each side
+	if textWidth <= 0 || len(rows) < 3 {
+		return
+	}
+	body, _ := wrapText([]rune(m.popupText), textWidth, true, m.tabWidth)
+	body = body[:min(len(body), len(rows)-2)]
+
+	box := make([]string, 0, len(body)+2)
+	box = append(box, m.borderWithLabel(m.theme.borderTopLeft, m.theme.borderTopRight, m.popupTitle, boxWidth-2))
+	vertical := string(m.theme.borderVertical)
+	for _, line := range body {
+		line, _ = expandTabs(line, m.tabWidth)
+		box = append(box, vertical+" "+render.PadOrTrim(line, textWidth)+" "+vertical)
+	}
+	box = append(box, m.borderWithLabel(m.theme.borderBottomLeft, m.theme.borderBottomRight, "", boxWidth-2))
+
+	top := (len(rows) - len(box)) / 2
+	left := strings.Repeat(" ", (innerWidth-boxWidth)/2)
+	right := strings.Repeat(" ", innerWidth-boxWidth-len(left))
+	for i, line := range box {
+		rows[top+i] = left + line + right
+	}
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"unicode"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// detectRTL reports whether text reads right to left, judged by the
+// bidi class of its first letter: Hebrew (R) and Arabic (AL) letters
+// make it RTL. Whitespace, digits and punctuation carry no direction
+// and are skipped.
+func detectRTL(text []rune) bool {
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		props, _ := bidi.LookupRune(r)
+		switch props.Class() {
+		case bidi.R, bidi.AL:
+			return true
+		case bidi.L:
+			return false
+		}
+	}
+	return false
+}
+
+// shiftIndexMap returns indexMap with every display column moved right
+// by shift, for a line that got shift columns of padding in front. A
+// nil map stands for the identity over n runes.
+func shiftIndexMap(indexMap []int, n, shift int) []int {
+	if shift <= 0 {
+		return indexMap
+	}
+	out := make([]int, 0, n+1)
+	if indexMap == nil {
+		for i := range n + 1 {
+			out = append(out, i+shift)
+		}
+		return out
+	}
+	for _, col := range indexMap {
+		out = append(out, col+shift)
+	}
+	return out
+}
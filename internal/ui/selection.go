@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"thujareader/internal/i18n"
+)
+
+// startSelection enters visual selection mode with an empty selection
+// at the start of the first visible line.
+func (m *Model) startSelection() {
+	if m.currentBook == nil || len(m.lineOffsets) == 0 {
+		m.setStatus(m.tr(i18n.MsgSelectNoBook))
+		return
+	}
+	m.selecting = true
+	m.selectStart = m.lineOffsets[min(max(m.topLine, 0), len(m.lineOffsets)-1)]
+	m.selectEnd = m.selectStart
+	m.setStatus(m.tr(i18n.MsgSelectHint))
+}
+
+// handleSelectionKey handles keys in visual selection mode: the arrows
+// move the end of the selection, Enter or y copies it, and Esc or v
+// leaves the mode.
+func (m *Model) handleSelectionKey(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.selecting = false
+		m.setStatus(m.tr(i18n.MsgCancelled))
+	case tea.KeyEnter:
+		m.copySelection()
+	case tea.KeyLeft:
+		m.moveSelectionEnd(m.selectEnd - 1)
+	case tea.KeyRight:
+		m.moveSelectionEnd(m.selectEnd + 1)
+	case tea.KeyUp:
+		m.moveSelectionLines(-1)
+	case tea.KeyDown:
+		m.moveSelectionLines(1)
+	case tea.KeyPgUp:
+		m.moveSelectionLines(-max(1, m.visibleLineCount()))
+	case tea.KeyPgDown:
+		m.moveSelectionLines(max(1, m.visibleLineCount()))
+	case tea.KeyRunes:
+		if len(msg.Runes) != 1 {
+			return false
+		}
+		switch msg.Runes[0] {
+		case 'y', 'Y':
+			m.copySelection()
+		case 'v':
+			m.selecting = false
+			m.setStatus(m.tr(i18n.MsgCancelled))
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// selectionRange returns the selected text as a half-open range of rune
+// offsets. The selection includes the runes at both ends.
+func (m Model) selectionRange() (from, to int) {
+	from, to = min(m.selectStart, m.selectEnd), max(m.selectStart, m.selectEnd)+1
+	return from, min(to, len(m.textRunes))
+}
+
+// moveSelectionEnd moves the end of the selection to offset, clamped to
+// the text, and scrolls it into view.
+func (m *Model) moveSelectionEnd(offset int) {
+	m.selectEnd = min(max(offset, 0), max(0, len(m.textRunes)-1))
+	m.scrollLineIntoView(m.lineOfOffset(m.selectEnd))
+}
+
+// scrollLineIntoView scrolls as little as needed to show the visual
+// line.
+func (m *Model) scrollLineIntoView(line int) {
+	switch height := max(1, m.visibleLineCount()); {
+	case line < m.topLine:
+		m.scrollBy(line - m.topLine)
+	case line >= m.topLine+height:
+		m.scrollBy(line - m.topLine - height + 1)
+	}
+}
+
+// moveSelectionLines moves the end of the selection delta visual lines
+// up or down, keeping its column where the target line is long enough.
+func (m *Model) moveSelectionLines(delta int) {
+	if len(m.lineOffsets) == 0 {
+		return
+	}
+	line := m.lineOfOffset(m.selectEnd)
+	col := m.selectEnd - m.lineOffsets[line]
+	target := min(max(line+delta, 0), len(m.lineOffsets)-1)
+	lineLen := utf8.RuneCountInString(m.lines[target])
+	m.moveSelectionEnd(m.lineOffsets[target] + min(col, max(0, lineLen-1)))
+}
+
+// lineOfOffset returns the visual line containing the rune offset.
+func (m Model) lineOfOffset(offset int) int {
+	line := sort.Search(len(m.lineOffsets), func(i int) bool {
+		return m.lineOffsets[i] > offset
+	}) - 1
+	return max(0, line)
+}
+
+// copySelection copies the selected text to the clipboard and leaves
+// selection mode; the outcome is reported by clipboardWrittenMsg.
+func (m *Model) copySelection() {
+	from, to := m.selectionRange()
+	m.selecting = false
+	if from >= to {
+		return
+	}
+	m.queueCmd(writeClipboardCmd(string(m.textRunes[from:to])))
+}
+
+// highlightSelection marks the part of the selection that falls on the
+// given visual line, like highlightSearchMatches does for matches.
+func (m Model) highlightSelection(line string, lineIdx int, indexMap []int) string {
+	if lineIdx < 0 || lineIdx >= len(m.lineOffsets) {
+		return line
+	}
+	lineStart := m.lineOffsets[lineIdx]
+	lineLen := utf8.RuneCountInString(m.lines[lineIdx])
+	sel0, sel1 := m.selectionRange()
+	from, to := max(sel0-lineStart, 0), min(sel1-lineStart, lineLen)
+	if from >= to {
+		return line
+	}
+	runes := []rune(line)
+	if indexMap != nil {
+		from, to = indexMap[from], indexMap[to]
+	}
+	from, to = min(from, len(runes)), min(to, len(runes))
+
+	var b strings.Builder
+	b.WriteString(string(runes[:from]))
+	b.WriteString(m.theme.applyHighlight(string(runes[from:to])))
+	b.WriteString(string(runes[to:]))
+	return b.String()
+}
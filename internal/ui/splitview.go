@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+
+	"thujareader/internal/i18n"
+	"thujareader/internal/render"
+)
+
+// splitPaneWidth is the width of the split view's TOC pane, including
+// the line separating it from the text.
+const splitPaneWidth = 25
+
+// toggleSplitView shows or hides the TOC pane and rewraps the text to
+// the width left for it.
+func (m *Model) toggleSplitView() {
+	m.splitView = !m.splitView
+	m.reflowKeepingPosition()
+	switch {
+	case !m.splitView:
+		m.setStatus(m.tr(i18n.MsgSplitViewOff))
+	case m.tocPaneWidth(max(0, m.width-2)) == 0 && m.currentBook != nil:
+		m.setStatus(m.tr(i18n.MsgSplitViewNoRoom))
+	default:
+		m.setStatus(m.tr(i18n.MsgSplitViewOn))
+	}
+}
+
+// tocPaneWidth returns the width the TOC pane takes from a main area
+// innerWidth cells wide: splitPaneWidth in split view, unless that
+// would leave less than minTextWidth for the text, and 0 otherwise.
+func (m Model) tocPaneWidth(innerWidth int) int {
+	if !m.splitView || m.currentBook == nil || innerWidth-splitPaneWidth < minTextWidth {
+		return 0
+	}
+	return splitPaneWidth
+}
+
+// currentTOCEntry returns the index of the TOC entry the reading
+// position is in, i.e. the last one starting at or before it, or -1.
+func (m Model) currentTOCEntry() int {
+	if m.currentBook == nil {
+		return -1
+	}
+	offset := m.positionToAbsoluteOffset(m.currentPos)
+	current, best := -1, -1
+	for i, entry := range m.currentBook.TOC {
+		if start := m.positionToAbsoluteOffset(entry.Pos); start <= offset && start >= best {
+			current, best = i, start
+		}
+	}
+	return current
+}
+
+// renderTOCPane returns height rows of the TOC pane, with the current
+// entry highlighted and scrolled to the middle where possible.
+func (m Model) renderTOCPane(height int) []string {
+	width := splitPaneWidth - 1
+	separator := string(m.theme.borderVertical)
+	toc := m.currentBook.TOC
+	current := m.currentTOCEntry()
+	top := min(max(0, current-height/2), max(0, len(toc)-height))
+
+	rows := make([]string, height)
+	for i := range rows {
+		idx := top + i
+		switch {
+		case idx < len(toc):
+			label := render.PadOrTrim(" "+toc[idx].Label, width)
+			if idx == current {
+				label = m.theme.applyHighlight(label)
+			}
+			rows[i] = label + separator
+		case i == 0 && len(toc) == 0:
+			rows[i] = m.theme.applyDim(render.PadOrTrim(" "+m.tr(i18n.LabelTOCPaneEmpty), width)) + separator
+		default:
+			rows[i] = strings.Repeat(" ", width) + separator
+		}
+	}
+	return rows
+}
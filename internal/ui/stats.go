@@ -0,0 +1,39 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"thujareader/internal/i18n"
+)
+
+// statsWPM is the reading speed, in words per minute, behind the
+// reading time in the statistics.
+const statsWPM = 200
+
+// statsLines returns the lines of the statistics screen for the open
+// book.
+func (m Model) statsLines() []string {
+	if m.currentBook == nil {
+		return nil
+	}
+	s := m.currentBook.Stats
+	minutes := s.ReadingMinutes(statsWPM)
+	return []string{
+		" " + m.currentBook.Book.Title,
+		"",
+		"   " + m.tr(i18n.LabelStatsCharacters, s.Characters),
+		"   " + m.tr(i18n.LabelStatsWords, s.Words),
+		"   " + m.tr(i18n.LabelStatsReadingTime, minutes/60, minutes%60, statsWPM),
+		"   " + m.tr(i18n.LabelStatsChapters, s.Chapters),
+		"   " + m.tr(i18n.LabelStatsBookmarks, len(m.currentBookmarks())),
+	}
+}
+
+// handleStatsKey closes the statistics screen on Esc or Enter and
+// swallows other keys while it is open.
+func (m *Model) handleStatsKey(msg tea.KeyMsg) bool {
+	if msg.Type == tea.KeyEsc || msg.Type == tea.KeyEnter {
+		m.statsOpen = false
+	}
+	return true
+}
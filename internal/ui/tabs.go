@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+
+	"thujareader/internal/i18n"
+	"thujareader/internal/reader"
+	"thujareader/internal/render"
+)
+
+// showBook displays book in the active tab, or in a new tab when newTab
+// is set or no tab exists yet. A book already open in another tab is
+// shown there instead, so a book never has two tabs.
+func (m *Model) showBook(book reader.LoadedBook, newTab bool) {
+	if book.SourcePath != "" {
+		for i, b := range m.books {
+			if i != m.activeBookIndex && b.SourcePath == book.SourcePath {
+				m.books[i] = book
+				m.activeBookIndex = i
+				m.setBook(book)
+				return
+			}
+		}
+	}
+	if newTab || len(m.books) == 0 {
+		m.books = append(m.books, book)
+		m.activeBookIndex = len(m.books) - 1
+	} else {
+		m.books[m.activeBookIndex] = book
+	}
+	m.setBook(book)
+}
+
+// switchTab activates the tab delta places after the active one,
+// wrapping around, and restores its reading position.
+func (m *Model) switchTab(delta int) {
+	if len(m.books) < 2 {
+		m.setStatus(m.tr(i18n.MsgTabsSingle))
+		return
+	}
+	n := len(m.books)
+	m.activeBookIndex = ((m.activeBookIndex+delta)%n + n) % n
+	m.setBook(m.books[m.activeBookIndex])
+	m.restoreSavedPosition()
+	m.setTransientStatus(m.tr(i18n.MsgTabSwitched, m.activeBookIndex+1, n, m.books[m.activeBookIndex].Book.Title))
+}
+
+// closeTab closes the active tab and shows the next one. The last tab
+// cannot be closed, since the reader always shows a book once one has
+// been opened.
+func (m *Model) closeTab() {
+	if len(m.books) < 2 {
+		m.setStatus(m.tr(i18n.MsgTabsSingle))
+		return
+	}
+	title := m.books[m.activeBookIndex].Book.Title
+	m.rememberPosition()
+	// setBook would remember the closed book's position again; the
+	// next book must not inherit it, so clear currentBook first.
+	m.currentBook = nil
+	m.books = slices.Delete(m.books, m.activeBookIndex, m.activeBookIndex+1)
+	m.activeBookIndex = min(m.activeBookIndex, len(m.books)-1)
+	m.setBook(m.books[m.activeBookIndex])
+	m.restoreSavedPosition()
+	m.setTransientStatus(m.tr(i18n.MsgTabClosed, title))
+}
+
+// RestoreTabs opens books in tabs, e.g. those open at the last exit,
+// and activates the one at index active at its saved position.
+func (m *Model) RestoreTabs(books []reader.LoadedBook, active int) {
+	if len(books) == 0 {
+		return
+	}
+	m.books = slices.Clone(books)
+	m.activeBookIndex = min(max(active, 0), len(books)-1)
+	book := m.books[m.activeBookIndex]
+	m.setBook(book)
+	m.restoreSavedPosition()
+	if book.SourcePath != "" {
+		m.addOpenedFile(book.SourcePath)
+	}
+}
+
+// ExportOpenBookPaths returns the source paths of the books open in
+// tabs, in tab order, so callers (e.g. main) can reopen them later.
+// Books without a path, such as those read from standard input, are
+// left out.
+func (m Model) ExportOpenBookPaths() []string {
+	var out []string
+	for _, b := range m.books {
+		if b.SourcePath != "" {
+			out = append(out, b.SourcePath)
+		}
+	}
+	return out
+}
+
+// showTabBar reports whether the tab bar is displayed; it is hidden
+// while only one book is open.
+func (m Model) showTabBar() bool {
+	return len(m.books) > 1
+}
+
+// renderTabBar renders one tab per open book, numbered and labelled
+// with the book title, with the active tab highlighted. Tabs share the
+// width equally.
+func (m Model) renderTabBar() string {
+	n := len(m.books)
+	tabWidth := max(4, m.width/max(1, n))
+	var b strings.Builder
+	used := 0
+	for i, book := range m.books {
+		title := strings.TrimSpace(book.Book.Title)
+		if title == "" && book.SourcePath != "" {
+			title = filepath.Base(book.SourcePath)
+		}
+		label := " " + strconv.Itoa(i+1) + ": " + title + " "
+		label = render.PadOrTrim(runewidth.Truncate(label, tabWidth-1, "… "), tabWidth-1)
+		if used+tabWidth > m.width {
+			break
+		}
+		if i == m.activeBookIndex {
+			b.WriteString(m.theme.applyHighlight(label))
+		} else {
+			b.WriteString(label)
+		}
+		b.WriteRune(m.theme.borderVertical)
+		used += tabWidth
+	}
+	b.WriteString(strings.Repeat(" ", max(0, m.width-used)))
+	return b.String()
+}
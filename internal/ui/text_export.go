@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"thujareader/internal/i18n"
+)
+
+// exportChunkSize is how many bytes exportTextCmd writes at a time, so
+// that progress can be shown while a large book is written.
+const exportChunkSize = 256 << 10
+
+// textExport is a plain text export being written in the background.
+type textExport struct {
+	file    *os.File
+	path    string
+	data    []byte
+	written int
+}
+
+// exportTextMsg reports that exportTextCmd wrote a chunk of an export,
+// or failed to.
+type exportTextMsg struct {
+	export *textExport
+	err    error
+}
+
+// exportTextCmd writes the next chunk of export; the result arrives as
+// an exportTextMsg.
+func exportTextCmd(export *textExport) tea.Cmd {
+	return func() tea.Msg {
+		end := min(export.written+exportChunkSize, len(export.data))
+		n, err := export.file.Write(export.data[export.written:end])
+		export.written += n
+		return exportTextMsg{export: export, err: err}
+	}
+}
+
+// defaultTextExportPath suggests a file name for exporting the open
+// book's text, next to the book when its location is known.
+func (m Model) defaultTextExportPath() string {
+	name := "book.txt"
+	if title := strings.TrimSpace(m.currentBook.Book.Title); title != "" {
+		name = title + ".txt"
+	}
+	return m.besideBook(name)
+}
+
+// exportText starts writing the open book's text to path. The loaders
+// have already removed any markup, so the text is written as it is;
+// text like "List<String>" in a programming book is content. The file
+// is written in chunks by exportTextCmd, and handleExportTextResult
+// shows the progress.
+func (m *Model) exportText(path string) {
+	if m.currentBook == nil {
+		m.setStatus(m.tr(i18n.MsgExportTextNoBook))
+		return
+	}
+	// Starting over would drop the open file of the running export.
+	if m.textExport != nil {
+		m.setStatus(m.tr(i18n.MsgExportTextBusy))
+		return
+	}
+	if path == "" {
+		m.setStatus(m.tr(i18n.MsgNoPath))
+		return
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		m.setStatus(m.tr(i18n.MsgExportFailed, err))
+		return
+	}
+	text := m.currentBook.Text
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	m.textExport = &textExport{file: file, path: path, data: []byte(text)}
+	m.setStatus(m.tr(i18n.MsgExportingText, 0))
+	m.queueCmd(exportTextCmd(m.textExport))
+}
+
+// handleExportTextResult shows the progress of the running export and
+// writes its next chunk, or reports how it ended.
+func (m *Model) handleExportTextResult(msg exportTextMsg) {
+	export := msg.export
+	if export != m.textExport {
+		return
+	}
+	if msg.err == nil && export.written < len(export.data) {
+		m.setStatus(m.tr(i18n.MsgExportingText, export.written*100/len(export.data)))
+		m.queueCmd(exportTextCmd(export))
+		return
+	}
+	m.textExport = nil
+	err := msg.err
+	if closeErr := export.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		m.setStatus(m.tr(i18n.MsgExportFailed, err))
+		return
+	}
+	m.setTransientStatus(m.tr(i18n.MsgTextExported, export.path))
+}
@@ -1,6 +1,11 @@
 package ui
 
-import "os"
+import (
+	"os"
+	"strings"
+
+	"thujareader/internal/render"
+)
 
 // Theme describes colors and basic pseudo-graphics characters used by
 // the TUI. It intentionally stays very small so it can be wired to a
@@ -9,7 +14,23 @@ type Theme struct {
 	// ANSI escape sequences (without reset) for the major regions.
 	menuBarPrefix   string
 	statusBarPrefix string
-	reset           string
+	titleBarPrefix  string
+	// mainAreaPrefix optionally colors the bordered main area; by
+	// default it uses the terminal's own colors.
+	mainAreaPrefix string
+	// borderTitlePrefix optionally colors text embedded in a border.
+	borderTitlePrefix string
+	highlightPrefix   string
+	// searchHighlight marks the current search match, so it stands out
+	// from the other highlighted matches.
+	searchHighlight string
+	dimPrefix       string
+	// codeBlockPrefix colors program code in technical books.
+	codeBlockPrefix string
+	// cursorPrefix and cursorSuffix wrap the input cursor character.
+	cursorPrefix string
+	cursorSuffix string
+	reset        string
 
 	// Box-drawing characters. For very limited terminals these can fall
 	// back to ASCII characters.
@@ -19,6 +40,13 @@ type Theme struct {
 	borderBottomRight rune
 	borderHorizontal  rune
 	borderVertical    rune
+
+	// Scrollbar characters for long lists.
+	scrollbarThumb rune
+	scrollbarTrack rune
+
+	// cursorRune marks the insertion point in the input line.
+	cursorRune rune
 }
 
 // DefaultTheme returns a theme approximating the classic DOS edit.exe
@@ -30,6 +58,17 @@ func DefaultTheme() Theme {
 		// Cyan menu bar on blue background with bright white text.
 		menuBarPrefix:   "\x1b[1;37;46m",
 		statusBarPrefix: "\x1b[1;37;44m",
+		// Black on light gray, like edit.exe's window title.
+		titleBarPrefix: "\x1b[30;47m",
+		// Reverse video, as edit.exe uses for selected text.
+		highlightPrefix: "\x1b[7m",
+		// Black on yellow for the match the search jumped to.
+		searchHighlight: "\x1b[30;43m",
+		dimPrefix:       "\x1b[2m",
+		// Cyan, like the listings in DOS programming manuals.
+		codeBlockPrefix: "\x1b[36m",
+		cursorPrefix:    "\x1b[7m",
+		cursorSuffix:    "\x1b[27m",
 		reset:           "\x1b[0m",
 
 		borderTopLeft:     '┌',
@@ -38,9 +77,123 @@ func DefaultTheme() Theme {
 		borderBottomRight: '┘',
 		borderHorizontal:  '─',
 		borderVertical:    '│',
+
+		scrollbarThumb: '█',
+		scrollbarTrack: '░',
+
+		cursorRune: '▮',
+	}
+}
+
+// LightTheme is DefaultTheme adapted to terminals with a light
+// background: the same bars, but dark text areas are avoided so the
+// status and menu bars stay readable next to black-on-white text.
+func LightTheme() Theme {
+	t := DefaultTheme()
+	// Black on cyan and white on blue read well on a white background.
+	t.menuBarPrefix = "\x1b[30;46m"
+	t.statusBarPrefix = "\x1b[37;44m"
+	// Black on white would vanish into the background; use dark gray.
+	t.titleBarPrefix = "\x1b[37;100m"
+	// Cyan text is faint on white; blue is not.
+	t.codeBlockPrefix = "\x1b[34m"
+	return t
+}
+
+// HighContrastTheme maximizes legibility for readers with low vision:
+// black text on a white page, a yellow status bar, white-on-black bars
+// and a heavy ASCII frame. It uses true-color sequences so the terminal
+// palette cannot soften the contrast.
+func HighContrastTheme() Theme {
+	const (
+		black  = "0;0;0"
+		white  = "255;255;255"
+		yellow = "255;255;0"
+		blue   = "0;0;160"
+	)
+	return Theme{
+		menuBarPrefix:   trueColor("1;", white, black),
+		statusBarPrefix: trueColor("1;", black, yellow),
+		titleBarPrefix:  trueColor("1;", white, black),
+		mainAreaPrefix:  trueColor("", black, white),
+		highlightPrefix: trueColor("1;", white, blue),
+		searchHighlight: trueColor("1;", black, yellow),
+		// Dimming would lower the contrast; underline instead.
+		dimPrefix:       "\x1b[4m",
+		codeBlockPrefix: trueColor("", blue, white),
+		cursorPrefix:    "\x1b[7m",
+		cursorSuffix:    "\x1b[27m",
+		reset:           "\x1b[0m",
+
+		borderTopLeft:     '#',
+		borderTopRight:    '#',
+		borderBottomLeft:  '#',
+		borderBottomRight: '#',
+		borderHorizontal:  '=',
+		borderVertical:    '#',
+
+		scrollbarThumb: '#',
+		scrollbarTrack: '.',
+
+		cursorRune: '█',
 	}
 }
 
+// trueColor returns the escape sequence for the foreground and
+// background colors fg and bg, given as "r;g;b", after the SGR
+// attributes attrs (e.g. "1;" for bold, or "").
+func trueColor(attrs, fg, bg string) string {
+	return "\x1b[" + attrs + "38;2;" + fg + ";48;2;" + bg + "m"
+}
+
+// The Solarized palette (https://ethanschoonover.com/solarized/).
+const (
+	solarBase03  = "0;43;54"
+	solarBase02  = "7;54;66"
+	solarBase01  = "88;110;117"
+	solarBase00  = "101;123;131"
+	solarBase0   = "131;148;150"
+	solarBase1   = "147;161;161"
+	solarBase2   = "238;232;213"
+	solarBase3   = "253;246;227"
+	solarYellow  = "181;137;0"
+	solarBlue    = "38;139;210"
+	solarCyan    = "42;161;152"
+	solarMagenta = "211;54;130"
+)
+
+// SolarizedDarkTheme uses the Solarized palette on its dark base: body
+// text in base0 on base03, bars on base02 and a blue status bar.
+func SolarizedDarkTheme() Theme {
+	t := DefaultTheme()
+	t.menuBarPrefix = trueColor("1;", solarBase1, solarBase02)
+	t.titleBarPrefix = trueColor("", solarBase1, solarBase02)
+	t.statusBarPrefix = trueColor("1;", solarBase3, solarBlue)
+	t.mainAreaPrefix = trueColor("", solarBase0, solarBase03)
+	t.borderTitlePrefix = trueColor("1;", solarCyan, solarBase03)
+	t.highlightPrefix = trueColor("", solarBase03, solarCyan)
+	t.searchHighlight = trueColor("", solarBase03, solarYellow)
+	t.dimPrefix = trueColor("", solarBase01, solarBase03)
+	t.codeBlockPrefix = trueColor("", solarCyan, solarBase03)
+	return t
+}
+
+// SolarizedLightTheme is SolarizedDarkTheme on the light base: body
+// text in base00 on base3 and bars on base2.
+func SolarizedLightTheme() Theme {
+	t := DefaultTheme()
+	t.menuBarPrefix = trueColor("1;", solarBase01, solarBase2)
+	t.titleBarPrefix = trueColor("", solarBase01, solarBase2)
+	t.statusBarPrefix = trueColor("1;", solarBase3, solarBlue)
+	t.mainAreaPrefix = trueColor("", solarBase00, solarBase3)
+	t.borderTitlePrefix = trueColor("1;", solarMagenta, solarBase3)
+	t.highlightPrefix = trueColor("", solarBase3, solarCyan)
+	t.searchHighlight = trueColor("", solarBase3, solarYellow)
+	t.dimPrefix = trueColor("", solarBase1, solarBase3)
+	t.codeBlockPrefix = trueColor("", solarCyan, solarBase3)
+	return t
+}
+
 // NoColorTheme provides a safe fallback for terminals without color
 // support. It keeps the same layout but omits ANSI sequences and
 // replaces box-drawing characters with ASCII where possible.
@@ -48,6 +201,9 @@ func NoColorTheme() Theme {
 	return Theme{
 		menuBarPrefix:   "",
 		statusBarPrefix: "",
+		titleBarPrefix:  "",
+		highlightPrefix: "",
+		dimPrefix:       "",
 		reset:           "",
 
 		borderTopLeft:     '+',
@@ -56,6 +212,11 @@ func NoColorTheme() Theme {
 		borderBottomRight: '+',
 		borderHorizontal:  '-',
 		borderVertical:    '|',
+
+		scrollbarThumb: '#',
+		scrollbarTrack: ':',
+
+		cursorRune: '_',
 	}
 }
 
@@ -72,6 +233,40 @@ func ThemeFromEnv() Theme {
 	return DefaultTheme()
 }
 
+// themeByName resolves a Config.ThemeOverride value to a theme. Names
+// are case-insensitive; an empty or unknown name falls back to
+// ThemeFromEnv so environment hints still apply, picking LightTheme
+// when scheme (see detectColorScheme) reports a light background.
+func themeByName(name, scheme string) Theme {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "default", "dark":
+		return DefaultTheme()
+	case "light":
+		return LightTheme()
+	case "no-color", "no_color", "mono":
+		return NoColorTheme()
+	case "high_contrast", "high-contrast":
+		return HighContrastTheme()
+	case "solarized-dark", "solarized_dark":
+		return SolarizedDarkTheme()
+	case "solarized-light", "solarized_light":
+		return SolarizedLightTheme()
+	}
+	if scheme == colorSchemeLight && os.Getenv("THUJAREADER_NO_COLOR") == "" {
+		return LightTheme()
+	}
+	return ThemeFromEnv()
+}
+
+// decor returns the subset of the theme used by the render package.
+func (t Theme) decor() render.Decor {
+	return render.Decor{
+		ScrollbarThumb: t.scrollbarThumb,
+		ScrollbarTrack: t.scrollbarTrack,
+		Dim:            t.applyDim,
+	}
+}
+
 // applyMenuBar colors a menu bar line according to the theme.
 func (t Theme) applyMenuBar(line string) string {
 	if t.menuBarPrefix == "" {
@@ -80,6 +275,16 @@ func (t Theme) applyMenuBar(line string) string {
 	return t.menuBarPrefix + line + t.reset
 }
 
+// applyMainArea colors a line of the bordered main area. Styles inside
+// the line end with a reset, after which the main area colors are
+// restored.
+func (t Theme) applyMainArea(line string) string {
+	if t.mainAreaPrefix == "" {
+		return line
+	}
+	return t.mainAreaPrefix + strings.ReplaceAll(line, t.reset, t.reset+t.mainAreaPrefix) + t.reset
+}
+
 // applyStatusBar colors a status bar line according to the theme.
 func (t Theme) applyStatusBar(line string) string {
 	if t.statusBarPrefix == "" {
@@ -87,3 +292,61 @@ func (t Theme) applyStatusBar(line string) string {
 	}
 	return t.statusBarPrefix + line + t.reset
 }
+
+// applyTitleBar colors the title line according to the theme.
+func (t Theme) applyTitleBar(line string) string {
+	if t.titleBarPrefix == "" {
+		return line
+	}
+	return t.titleBarPrefix + line + t.reset
+}
+
+// applyBorderTitle colors text embedded in a border line.
+func (t Theme) applyBorderTitle(text string) string {
+	if t.borderTitlePrefix == "" {
+		return text
+	}
+	return t.borderTitlePrefix + text + t.reset
+}
+
+// applyHighlight marks a span of text (e.g. a search match) according
+// to the theme.
+func (t Theme) applyHighlight(text string) string {
+	if t.highlightPrefix == "" {
+		return text
+	}
+	return t.highlightPrefix + text + t.reset
+}
+
+// applySearchHighlight marks the current search match. Themes without
+// a dedicated style fall back to the ordinary highlight.
+func (t Theme) applySearchHighlight(text string) string {
+	if t.searchHighlight == "" {
+		return t.applyHighlight(text)
+	}
+	return t.searchHighlight + text + t.reset
+}
+
+// applyCursor renders the input cursor. Unlike the other styles it
+// only undoes its own attribute, so the surrounding line keeps its
+// colors.
+func (t Theme) applyCursor(text string) string {
+	return t.cursorPrefix + text + t.cursorSuffix
+}
+
+// applyCode renders program code from a technical book.
+func (t Theme) applyCode(text string) string {
+	if t.codeBlockPrefix == "" {
+		return text
+	}
+	return t.codeBlockPrefix + text + t.reset
+}
+
+// applyDim renders secondary information (e.g. a missing-file marker)
+// in a dimmed style.
+func (t Theme) applyDim(text string) string {
+	if t.dimPrefix == "" {
+		return text
+	}
+	return t.dimPrefix + text + t.reset
+}
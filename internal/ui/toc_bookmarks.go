@@ -0,0 +1,27 @@
+package ui
+
+import "thujareader/internal/reader"
+
+// addTOCBookmarks bookmarks every TOC entry of a book opened for the
+// first time, i.e. one with neither a remembered position nor
+// bookmarks. Later opens keep whatever the user made of them.
+func (m *Model) addTOCBookmarks() {
+	id := m.currentBook.Book.ID
+	if id == "" || len(m.currentBook.TOC) == 0 {
+		return
+	}
+	if _, ok := m.positions[id]; ok {
+		return
+	}
+	if _, ok := m.bookmarks[id]; ok {
+		return
+	}
+	list := make([]reader.Bookmark, len(m.currentBook.TOC))
+	for i, entry := range m.currentBook.TOC {
+		list[i] = reader.Bookmark{Name: entry.Label, BookID: id, Pos: entry.Pos, FromTOC: true}
+	}
+	if m.bookmarks == nil {
+		m.bookmarks = make(map[reader.BookID][]reader.Bookmark)
+	}
+	m.bookmarks[id] = list
+}